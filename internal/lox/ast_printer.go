@@ -18,10 +18,32 @@ func (a *AstPrinter) Print(e Expr) string {
 	return a.String()
 }
 
+// PrintStatement renders a statement as an s-expression, in the same style
+// Print uses for expressions.
+func (a *AstPrinter) PrintStatement(s Stmt) string {
+	a.Reset()
+	s.Accept(a)
+	return a.String()
+}
+
+func (a *AstPrinter) VisitAssign(as *Assign) {
+	a.parenthesize("= "+as.Name.Lexeme, as.Value)
+}
+
 func (a *AstPrinter) VisitBinary(b Binary) {
 	a.parenthesize(b.Operator.Lexeme, b.Left, b.Right)
 }
 
+func (a *AstPrinter) VisitCall(c Call) {
+	a.parenthesize("call", append([]Expr{c.Callee}, c.Arguments...)...)
+}
+
+func (a *AstPrinter) VisitGet(g Get) {
+	a.WriteString("(. ")
+	g.Object.Accept(a)
+	a.WriteString(" " + g.Name.Lexeme + ")")
+}
+
 func (a *AstPrinter) VisitGrouping(g Grouping) {
 	a.parenthesize("group", g.Expr)
 }
@@ -34,10 +56,34 @@ func (a *AstPrinter) VisitLiteral(l Literal) {
 	a.WriteString(l.Value.Lexeme)
 }
 
+func (a *AstPrinter) VisitLogical(l Logical) {
+	a.parenthesize(l.Operator.Lexeme, l.Left, l.Right)
+}
+
+func (a *AstPrinter) VisitSet(s Set) {
+	a.WriteString("(= ")
+	Get{Object: s.Object, Name: s.Name}.Accept(a)
+	a.WriteString(" ")
+	s.Value.Accept(a)
+	a.WriteString(")")
+}
+
+func (a *AstPrinter) VisitSuper(s *SuperExpr) {
+	a.WriteString("(super " + s.Method.Lexeme + ")")
+}
+
+func (a *AstPrinter) VisitThis(_ *ThisExpr) {
+	a.WriteString("this")
+}
+
 func (a *AstPrinter) VisitUnary(u Unary) {
 	a.parenthesize(u.Operator.Lexeme, u.Right)
 }
 
+func (a *AstPrinter) VisitVariable(v *Variable) {
+	a.WriteString(v.Name.Lexeme)
+}
+
 func (a *AstPrinter) parenthesize(label string, exprs ...Expr) {
 	a.WriteString("(")
 	a.WriteString(label)
@@ -47,3 +93,79 @@ func (a *AstPrinter) parenthesize(label string, exprs ...Expr) {
 	}
 	a.WriteString(")")
 }
+
+func (a *AstPrinter) VisitBlockStmt(s BlockStmt) {
+	a.WriteString("(block")
+	a.statements(s.Statements)
+	a.WriteString(")")
+}
+
+func (a *AstPrinter) VisitClassStmt(s ClassStmt) {
+	a.WriteString("(class " + s.Name.Lexeme)
+	if s.Superclass != nil {
+		a.WriteString(" < " + s.Superclass.Name.Lexeme)
+	}
+	for _, method := range s.Methods {
+		a.WriteString(" ")
+		method.Accept(a)
+	}
+	a.WriteString(")")
+}
+
+func (a *AstPrinter) VisitExpressionStmt(s ExpressionStmt) {
+	a.parenthesize(";", s.Expr)
+}
+
+func (a *AstPrinter) VisitFunctionStmt(s FunctionStmt) {
+	params := make([]string, len(s.Params))
+	for i, param := range s.Params {
+		params[i] = param.Lexeme
+	}
+	a.WriteString("(fun " + s.Name.Lexeme + " (" + strings.Join(params, " ") + ")")
+	a.statements(s.Body)
+	a.WriteString(")")
+}
+
+func (a *AstPrinter) VisitIfStmt(s IfStmt) {
+	a.WriteString("(if ")
+	s.Condition.Accept(a)
+	a.statements([]Stmt{s.ThenBranch})
+	if s.ElseBranch != nil {
+		a.statements([]Stmt{s.ElseBranch})
+	}
+	a.WriteString(")")
+}
+
+func (a *AstPrinter) VisitPrintStmt(s PrintStmt) {
+	a.parenthesize("print", s.Expr)
+}
+
+func (a *AstPrinter) VisitReturnStmt(s ReturnStmt) {
+	if s.Value == nil {
+		a.WriteString("(return)")
+		return
+	}
+	a.parenthesize("return", s.Value)
+}
+
+func (a *AstPrinter) VisitVarStmt(s VarStmt) {
+	if s.Initializer == nil {
+		a.WriteString("(var " + s.Name.Lexeme + ")")
+		return
+	}
+	a.parenthesize("var "+s.Name.Lexeme, s.Initializer)
+}
+
+func (a *AstPrinter) VisitWhileStmt(s WhileStmt) {
+	a.WriteString("(while ")
+	s.Condition.Accept(a)
+	a.statements([]Stmt{s.Body})
+	a.WriteString(")")
+}
+
+func (a *AstPrinter) statements(stmts []Stmt) {
+	for _, s := range stmts {
+		a.WriteString(" ")
+		s.Accept(a)
+	}
+}
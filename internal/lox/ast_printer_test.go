@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAstPrinter(t *testing.T) {
@@ -26,3 +27,61 @@ func TestAstPrinter(t *testing.T) {
 
 	assert.Equal(t, "(* (- 123) (group 45.67))", printer.Print(e))
 }
+
+func TestAstPrinter_Variables(t *testing.T) {
+	printer := NewAstPrinter()
+
+	e := &Assign{
+		Name:  NewToken(Identifier, "a", nil, 1),
+		Value: &Variable{Name: NewToken(Identifier, "b", nil, 1)},
+	}
+
+	assert.Equal(t, "(= a b)", printer.Print(e))
+}
+
+func TestAstPrinter_Properties(t *testing.T) {
+	printer := NewAstPrinter()
+
+	e := Set{
+		Object: &ThisExpr{Keyword: NewToken(This, "this", nil, 1)},
+		Name:   NewToken(Identifier, "a", nil, 1),
+		Value: Get{
+			Object: &Variable{Name: NewToken(Identifier, "b", nil, 1)},
+			Name:   NewToken(Identifier, "c", nil, 1),
+		},
+	}
+
+	assert.Equal(t, "(= (. this a) (. b c))", printer.Print(e))
+}
+
+func TestAstPrinter_Statements(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"1 + 2;", "(; (+ 1 2))"},
+		{"print \"hi\";", `(print "hi")`},
+		{"var a;", "(var a)"},
+		{"var a = nil;", "(var a nil)"},
+		{"{ var a = 1; print a; }", "(block (var a 1) (print a))"},
+		{"if (a) print 1; else print 2;", "(if a (print 1) (print 2))"},
+		{"while (a < 3) a = a + 1;", "(while (< a 3) (; (= a (+ a 1))))"},
+		{"fun add(a, b) { return a + b; }", "(fun add (a b) (return (+ a b)))"},
+		{"fun f() { return; }", "(fun f () (return))"},
+		{"class B < A { init() { this.x = 1; } }", "(class B < A (fun init () (; (= (. this x) 1))))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			lox := &Lox{}
+			scanner := NewScanner(tt.source, lox)
+			parser := NewParser(scanner.ScanTokens(), lox)
+			statements, err := parser.Parse()
+			require.NoError(t, err)
+			require.Len(t, statements, 1)
+
+			printer := NewAstPrinter()
+			assert.Equal(t, tt.expected, printer.PrintStatement(statements[0]))
+		})
+	}
+}
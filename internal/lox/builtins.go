@@ -0,0 +1,128 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"time"
+)
+
+// builtins creates the natives that need a capability to be installed,
+// bound to l so that output goes to its writer.
+func builtins(l *Lox) []*NativeFunction {
+	return []*NativeFunction{
+		newBuiltin("clock", CapabilityTime, 0, func([]any) (any, error) {
+			return float64(time.Now().UnixNano()) / float64(time.Second), nil
+		}),
+		newBuiltin("getenv", CapabilityEnv, 1, func(arguments []any) (any, error) {
+			name, err := stringArgument("getenv", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			if value, ok := os.LookupEnv(name); ok {
+				return value, nil
+			}
+			return nil, nil
+		}),
+		newBuiltin("readFile", CapabilityFSRead, 1, func(arguments []any) (any, error) {
+			path, err := stringArgument("readFile", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return nil, ioError("can't read '%s': %w", path, err)
+			}
+			return string(contents), nil
+		}),
+		newBuiltin("writeFile", CapabilityFSWrite, 2, func(arguments []any) (any, error) {
+			path, err := stringArgument("writeFile", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			contents, err := stringArgument("writeFile", arguments, 1)
+			if err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+				return nil, ioError("can't write '%s': %w", path, err)
+			}
+			return nil, nil
+		}),
+		newBuiltin("write", CapabilityStdout, 1, func(arguments []any) (any, error) {
+			_, _ = fmt.Fprint(l.output(), stringify(arguments[0]))
+			return nil, nil
+		}),
+	}
+}
+
+func newBuiltin(name string, capability Capability, arity int, fn func([]any) (any, error)) *NativeFunction {
+	native := NewNativeFunction(name, arity, fn)
+	native.capability = capability
+	return native
+}
+
+// installBuiltins defines every builtin whose capability is granted as a
+// global, and removes any whose capability has since been revoked. Globals
+// a script or host has bound to a builtin's name are left alone either way.
+func (i *Interpreter) installBuiltins() {
+	for _, native := range builtins(i.lox) {
+		current, defined := i.globals.values[native.Name]
+		if defined && !isBuiltin(current) {
+			continue
+		}
+		if i.lox.capabilities.Has(native.capability) {
+			i.globals.Define(native.Name, native)
+		} else if defined {
+			delete(i.globals.values, native.Name)
+		}
+	}
+}
+
+// isBuiltin reports whether value is one of the capability-gated builtins.
+func isBuiltin(value any) bool {
+	native, ok := value.(*NativeFunction)
+	return ok && native.capability != ""
+}
+
+// stringArgument returns the argument at idx if it is a string.
+func stringArgument(native string, arguments []any, idx int) (string, error) {
+	s, ok := arguments[idx].(string)
+	if !ok {
+		return "", InvalidArgument(native, idx, "a string", arguments[idx])
+	}
+	return s, nil
+}
+
+// numberArgument returns the argument at idx if it is a number.
+func numberArgument(native string, arguments []any, idx int) (float64, error) {
+	n, ok := arguments[idx].(float64)
+	if !ok {
+		return 0, InvalidArgument(native, idx, "a number", arguments[idx])
+	}
+	return n, nil
+}
+
+// integerArgument returns the argument at idx if it is a whole number.
+func integerArgument(native string, arguments []any, idx int) (int, error) {
+	n, ok := arguments[idx].(float64)
+	if !ok || n != math.Trunc(n) || math.Abs(n) > math.MaxInt32 {
+		return 0, InvalidArgument(native, idx, "an integer", arguments[idx])
+	}
+	return int(n), nil
+}
+
+// ioError reports a failed host operation without the Go operation name
+// that *fs.PathError adds.
+func ioError(format, path string, err error) *RuntimeError {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return &RuntimeError{
+		Err:  fmt.Errorf(format, path, err),
+		Code: CodeIO,
+	}
+}
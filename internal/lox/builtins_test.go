@@ -0,0 +1,162 @@
+package lox
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltins_InstalledByCapability(t *testing.T) {
+	tests := []struct {
+		capability Capability
+		name       string
+	}{
+		{CapabilityTime, "clock"},
+		{CapabilityEnv, "getenv"},
+		{CapabilityFSRead, "readFile"},
+		{CapabilityFSWrite, "writeFile"},
+		{CapabilityStdout, "write"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Lox{}
+			l.SetOutput(io.Discard, io.Discard)
+
+			_, err := l.Eval(context.Background(), tt.name+";")
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, CodeUndefinedVariable, runtimeErr.Code)
+
+			l.SetCapabilities(NewCapabilities(tt.capability))
+			value, err := l.Eval(context.Background(), tt.name+";")
+			require.NoError(t, err)
+			assert.Equal(t, "function", TypeName(value))
+
+			l.SetCapabilities(nil)
+			_, err = l.Eval(context.Background(), tt.name+";")
+			assert.ErrorAs(t, err, &runtimeErr)
+		})
+	}
+}
+
+func TestBuiltins_RevokingKeepsHostNatives(t *testing.T) {
+	l := &Lox{}
+	l.SetCapabilities(NewCapabilities(CapabilityTime))
+	l.Define("clock", NewNativeFunction("clock", 0, func([]any) (any, error) { return 42.0, nil }))
+
+	l.SetCapabilities(nil)
+	value, err := l.Eval(context.Background(), "clock();")
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, value)
+}
+
+func TestBuiltins_GrantingKeepsExistingGlobals(t *testing.T) {
+	l := &Lox{}
+	l.SetOutput(io.Discard, io.Discard)
+	l.Define("getenv", NewNativeFunction("getenv", 1, func([]any) (any, error) { return "host", nil }))
+	_, err := l.Eval(context.Background(), `fun write(s) { return "script"; }`)
+	require.NoError(t, err)
+
+	l.SetCapabilities(NewCapabilities(CapabilityEnv, CapabilityStdout))
+	value, err := l.Eval(context.Background(), `getenv("HOME") + " " + write("x");`)
+	require.NoError(t, err)
+	assert.Equal(t, "host script", value)
+}
+
+func TestBuiltins(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.txt")
+	require.NoError(t, os.WriteFile(input, []byte("hello"), 0o644))
+	output := filepath.Join(dir, "out.txt")
+	t.Setenv("LOX_BUILTINS_TEST", "set")
+
+	var stdout bytes.Buffer
+	l := &Lox{}
+	l.SetOutput(&stdout, io.Discard)
+	l.SetCapabilities(NewCapabilities(AllCapabilities...))
+	ctx := context.Background()
+
+	value, err := l.Eval(ctx, `readFile("`+input+`");`)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", value)
+
+	_, err = l.Eval(ctx, `writeFile("`+output+`", "written");`)
+	require.NoError(t, err)
+	contents, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "written", string(contents))
+
+	value, err = l.Eval(ctx, `getenv("LOX_BUILTINS_TEST");`)
+	require.NoError(t, err)
+	assert.Equal(t, "set", value)
+
+	value, err = l.Eval(ctx, `getenv("LOX_BUILTINS_TEST_UNSET");`)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = l.Eval(ctx, `clock() > 0;`)
+	require.NoError(t, err)
+	assert.Equal(t, true, value)
+
+	_, err = l.Eval(ctx, `write("a"); write("b");`)
+	require.NoError(t, err)
+	assert.Equal(t, "ab", stdout.String())
+}
+
+func TestBuiltins_Errors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+
+	tests := []struct {
+		name            string
+		source          string
+		expectedCode    string
+		expectedMessage string
+	}{
+		{
+			name:            "non-string path",
+			source:          "readFile(1);",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 1 to readFile must be a string, got number",
+		},
+		{
+			name:            "non-string contents",
+			source:          `writeFile("x", nil);`,
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 2 to writeFile must be a string, got nil",
+		},
+		{
+			name:            "missing file",
+			source:          `readFile("` + missing + `");`,
+			expectedCode:    CodeIO,
+			expectedMessage: "can't read '" + missing + "': no such file or directory",
+		},
+		{
+			name:            "non-string variable name",
+			source:          "getenv(true);",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 1 to getenv must be a string, got boolean",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Lox{}
+			l.SetOutput(io.Discard, io.Discard)
+			l.SetCapabilities(NewCapabilities(AllCapabilities...))
+
+			_, err := l.Eval(context.Background(), tt.source)
+
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, tt.expectedCode, runtimeErr.Code)
+			assert.Equal(t, tt.expectedMessage, runtimeErr.Error())
+		})
+	}
+}
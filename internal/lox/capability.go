@@ -0,0 +1,77 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Capability names a kind of access to the host that some builtins need.
+// Builtins are only installed into globals when their capability has been
+// granted, so scripts get no I/O by default.
+type Capability string
+
+const (
+	CapabilityFSRead  Capability = "fs.read"
+	CapabilityFSWrite Capability = "fs.write"
+	CapabilityTime    Capability = "time"
+	CapabilityEnv     Capability = "env"
+	CapabilityStdout  Capability = "stdout"
+)
+
+// AllCapabilities lists every capability a host can grant.
+var AllCapabilities = []Capability{
+	CapabilityFSRead,
+	CapabilityFSWrite,
+	CapabilityTime,
+	CapabilityEnv,
+	CapabilityStdout,
+}
+
+// Capabilities is a set of granted capabilities. The zero value grants
+// nothing.
+type Capabilities map[Capability]bool
+
+// NewCapabilities creates a set granting caps.
+func NewCapabilities(caps ...Capability) Capabilities {
+	set := make(Capabilities, len(caps))
+	for _, c := range caps {
+		set[c] = true
+	}
+	return set
+}
+
+// Has reports whether capability has been granted.
+func (c Capabilities) Has(capability Capability) bool {
+	return c[capability]
+}
+
+// ParseCapabilities parses a comma-separated list of capability names such
+// as "fs.read,time". The name "all" grants every capability.
+func ParseCapabilities(list string) (Capabilities, error) {
+	set := Capabilities{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			continue
+		case name == "all":
+			for _, c := range AllCapabilities {
+				set[c] = true
+			}
+		case isCapability(Capability(name)):
+			set[Capability(name)] = true
+		default:
+			return nil, fmt.Errorf("unknown capability %q", name)
+		}
+	}
+	return set, nil
+}
+
+func isCapability(capability Capability) bool {
+	for _, c := range AllCapabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
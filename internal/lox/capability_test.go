@@ -0,0 +1,64 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     string
+		expected Capabilities
+		wantErr  bool
+	}{
+		{
+			name:     "empty",
+			list:     "",
+			expected: Capabilities{},
+		},
+		{
+			name:     "single",
+			list:     "time",
+			expected: NewCapabilities(CapabilityTime),
+		},
+		{
+			name:     "several with spaces",
+			list:     "fs.read, env ,stdout",
+			expected: NewCapabilities(CapabilityFSRead, CapabilityEnv, CapabilityStdout),
+		},
+		{
+			name:     "all",
+			list:     "all",
+			expected: NewCapabilities(AllCapabilities...),
+		},
+		{
+			name:    "unknown",
+			list:    "time,network",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities, err := ParseCapabilities(tt.list)
+			if tt.wantErr {
+				assert.EqualError(t, err, `unknown capability "network"`)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, capabilities)
+		})
+	}
+}
+
+func TestCapabilities_Has(t *testing.T) {
+	var none Capabilities
+	assert.False(t, none.Has(CapabilityTime))
+
+	granted := NewCapabilities(CapabilityTime)
+	assert.True(t, granted.Has(CapabilityTime))
+	assert.False(t, granted.Has(CapabilityFSWrite))
+}
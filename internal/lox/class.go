@@ -0,0 +1,99 @@
+package lox
+
+// LoxClass is the runtime representation of a class declaration. Calling a
+// class constructs a new instance of it.
+type LoxClass struct {
+	Name       string
+	superclass *LoxClass
+	methods    map[string]*LoxFunction
+}
+
+// NewLoxClass creates a class with the given methods. superclass may be nil.
+func NewLoxClass(name string, superclass *LoxClass, methods map[string]*LoxFunction) *LoxClass {
+	return &LoxClass{
+		Name:       name,
+		superclass: superclass,
+		methods:    methods,
+	}
+}
+
+// FindMethod looks up a method declared on the class, falling back to its
+// chain of superclasses.
+func (c *LoxClass) FindMethod(name string) (*LoxFunction, bool) {
+	if method, ok := c.methods[name]; ok {
+		return method, true
+	}
+	if c.superclass != nil {
+		return c.superclass.FindMethod(name)
+	}
+	return nil, false
+}
+
+// Arity is the arity of the class's initializer, or zero if it has none.
+func (c *LoxClass) Arity() int {
+	if initializer, ok := c.FindMethod("init"); ok {
+		return initializer.Arity()
+	}
+	return 0
+}
+
+// Call creates a new instance and runs its initializer, if any.
+func (c *LoxClass) Call(interpreter *Interpreter, arguments []any) (any, error) {
+	if err := interpreter.allocate(); err != nil {
+		return nil, err
+	}
+	instance := NewLoxInstance(c)
+	if initializer, ok := c.FindMethod("init"); ok {
+		if _, err := initializer.Bind(instance).Call(interpreter, arguments); err != nil {
+			return nil, err
+		}
+	}
+	return instance, nil
+}
+
+func (c *LoxClass) String() string {
+	return c.Name
+}
+
+// Object is implemented by values whose properties scripts can get and set
+// with dot syntax: Lox instances, and Go values bound by a host.
+type Object interface {
+	Get(name Token) (any, error)
+	Set(name Token, value any) error
+}
+
+// LoxInstance is an object created by calling a LoxClass.
+type LoxInstance struct {
+	class  *LoxClass
+	fields map[string]any
+}
+
+// NewLoxInstance creates an instance of class with no fields set.
+func NewLoxInstance(class *LoxClass) *LoxInstance {
+	return &LoxInstance{
+		class:  class,
+		fields: map[string]any{},
+	}
+}
+
+// Get returns the named field, or else the named method bound to this
+// instance. Fields shadow methods.
+func (li *LoxInstance) Get(name Token) (any, error) {
+	if value, ok := li.fields[name.Lexeme]; ok {
+		return value, nil
+	}
+	if method, ok := li.class.FindMethod(name.Lexeme); ok {
+		return method.Bind(li), nil
+	}
+	return nil, newRuntimeError(name, CodeUndefinedProperty, "undefined property '%s'", name.Lexeme)
+}
+
+// Set creates or overwrites the named field. It never fails.
+func (li *LoxInstance) Set(name Token, value any) error {
+	li.fields[name.Lexeme] = value
+	return nil
+}
+
+func (li *LoxInstance) String() string {
+	return li.class.Name + " instance"
+}
@@ -0,0 +1,75 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoxClass(t *testing.T) {
+	initializer := NewLoxFunction(FunctionStmt{
+		Name:   NewToken(Identifier, "init", nil, 1),
+		Params: []Token{NewToken(Identifier, "a", nil, 1)},
+	}, NewEnvironment(nil), true)
+	class := NewLoxClass("Point", nil, map[string]*LoxFunction{"init": initializer})
+
+	assert.Equal(t, 1, class.Arity())
+	assert.Equal(t, "Point", class.String())
+
+	method, ok := class.FindMethod("init")
+	assert.True(t, ok)
+	assert.Same(t, initializer, method)
+
+	_, ok = class.FindMethod("missing")
+	assert.False(t, ok)
+}
+
+func TestLoxClass_InheritedMethods(t *testing.T) {
+	method := NewLoxFunction(FunctionStmt{
+		Name: NewToken(Identifier, "speak", nil, 1),
+	}, NewEnvironment(nil), false)
+	override := NewLoxFunction(FunctionStmt{
+		Name: NewToken(Identifier, "speak", nil, 1),
+	}, NewEnvironment(nil), false)
+
+	base := NewLoxClass("Animal", nil, map[string]*LoxFunction{"speak": method})
+	inheriting := NewLoxClass("Dog", base, map[string]*LoxFunction{})
+	overriding := NewLoxClass("Cat", base, map[string]*LoxFunction{"speak": override})
+
+	found, ok := inheriting.FindMethod("speak")
+	assert.True(t, ok)
+	assert.Same(t, method, found)
+
+	found, ok = overriding.FindMethod("speak")
+	assert.True(t, ok)
+	assert.Same(t, override, found)
+}
+
+func TestLoxClass_ArityWithoutInitializer(t *testing.T) {
+	class := NewLoxClass("Empty", nil, map[string]*LoxFunction{})
+
+	assert.Equal(t, 0, class.Arity())
+}
+
+func TestLoxInstance(t *testing.T) {
+	method := NewLoxFunction(FunctionStmt{
+		Name: NewToken(Identifier, "greet", nil, 1),
+	}, NewEnvironment(nil), false)
+	class := NewLoxClass("Greeter", nil, map[string]*LoxFunction{"greet": method})
+	instance := NewLoxInstance(class)
+
+	assert.Equal(t, "Greeter instance", instance.String())
+
+	require.NoError(t, instance.Set(NewToken(Identifier, "name", nil, 1), "lox"))
+	value, err := instance.Get(NewToken(Identifier, "name", nil, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "lox", value)
+
+	bound, err := instance.Get(NewToken(Identifier, "greet", nil, 1))
+	require.NoError(t, err)
+	assert.Equal(t, instance, bound.(*LoxFunction).closure.GetAt(0, "this"))
+
+	_, err = instance.Get(NewToken(Identifier, "missing", nil, 1))
+	assert.EqualError(t, err, "undefined property 'missing'")
+}
@@ -0,0 +1,197 @@
+package lox
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Severity ranks how serious a Diagnostic is.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Phase identifies which stage of the interpreter produced a Diagnostic.
+type Phase int
+
+const (
+	PhaseScan Phase = iota
+	PhaseParse
+	PhaseResolve
+	PhaseRuntime
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseScan:
+		return "scan"
+	case PhaseParse:
+		return "parse"
+	case PhaseResolve:
+		return "resolve"
+	case PhaseRuntime:
+		return "runtime"
+	default:
+		return fmt.Sprintf("Phase(%d)", int(p))
+	}
+}
+
+// Stable codes identifying the kind of problem a Diagnostic describes.
+const (
+	CodeUnexpectedCharacter = "unexpected-character"
+	CodeUnterminatedString  = "unterminated-string"
+	CodeInvalidNumber       = "invalid-number"
+	CodeSyntax              = "syntax-error"
+	CodeResolution          = "resolution-error"
+	CodeRuntime             = "runtime-error"
+)
+
+// Source is a program's text and the file it was read from. Tokens keep a
+// pointer to the Source they were scanned from, so code defined by one run
+// in a session still reports errors against its own file and text.
+type Source struct {
+	File string
+	Text string
+}
+
+// Span locates a Diagnostic in its source. Start and End are byte offsets;
+// Text is the source text of the offending token, if there is one.
+type Span struct {
+	File         string
+	Line, Column int
+	Start, End   int
+	Text         string
+}
+
+// Diagnostic is a single problem found while scanning, parsing, resolving
+// or running a program. Runtime diagnostics also carry the stack trace.
+type Diagnostic struct {
+	Severity Severity
+	Phase    Phase
+	Span     Span
+	Message  string
+	Code     string
+	Trace    []CallFrame
+}
+
+// DiagnosticSink receives every Diagnostic that Lox reports.
+type DiagnosticSink interface {
+	Report(d Diagnostic)
+}
+
+// TextSink writes diagnostics in the interpreter's traditional plain text
+// format.
+type TextSink struct {
+	w io.Writer
+}
+
+// NewTextSink creates a TextSink that writes to w.
+func NewTextSink(w io.Writer) *TextSink {
+	return &TextSink{w: w}
+}
+
+// Report writes d to the sink's writer. Runtime errors print the message
+// followed by the location, or by one location per frame of the stack trace
+// if there is one; everything else prints the location first.
+func (s *TextSink) Report(d Diagnostic) {
+	if d.Phase == PhaseRuntime {
+		_, _ = fmt.Fprintf(s.w, "%s\n", d.Message)
+		if len(d.Trace) == 0 {
+			_, _ = fmt.Fprintf(s.w, "[%s]\n", location(d.Span))
+		}
+		repeats := 0
+		for idx, frame := range d.Trace {
+			if idx > 0 && frame == d.Trace[idx-1] {
+				repeats++
+				if repeats >= maxRepeatedFrames {
+					continue
+				}
+			} else {
+				s.reportRepeats(repeats)
+				repeats = 0
+			}
+			function := "script"
+			if frame.Function != "" {
+				function = frame.Function + "()"
+			}
+			_, _ = fmt.Fprintf(s.w, "[%s] in %s\n", location(frame.Span), function)
+		}
+		s.reportRepeats(repeats)
+		return
+	}
+
+	where := ""
+	if d.Phase != PhaseScan {
+		if d.Span.Text == "" {
+			where = " at end"
+		} else {
+			where = " at '" + d.Span.Text + "'"
+		}
+	}
+	label := "Error"
+	if d.Severity == SeverityWarning {
+		label = "Warning"
+	}
+	_, _ = fmt.Fprintf(s.w, "%s: %s%s: %s\n", location(d.Span), label, where, d.Message)
+}
+
+// maxRepeatedFrames is how many identical consecutive frames are printed
+// before the rest of a run, as deep recursion produces, is summarized.
+const maxRepeatedFrames = 3
+
+func (s *TextSink) reportRepeats(repeats int) {
+	if hidden := repeats - maxRepeatedFrames + 1; hidden > 0 {
+		_, _ = fmt.Fprintf(s.w, "[previous frame repeated %d more times]\n", hidden)
+	}
+}
+
+// location formats a span as file:line:col, which most editors can jump to.
+// Input that didn't come from a file is reported as <stdin>.
+func location(span Span) string {
+	file := span.File
+	if file == "" {
+		file = "<stdin>"
+	}
+	return fmt.Sprintf("%s:%d:%d", file, span.Line, span.Column)
+}
+
+// DiagnosticCollector is a DiagnosticSink that keeps every diagnostic in
+// memory, for hosts that want to inspect or render them later.
+type DiagnosticCollector struct {
+	Diagnostics []Diagnostic
+}
+
+// Report appends d to the collected diagnostics.
+func (c *DiagnosticCollector) Report(d Diagnostic) {
+	c.Diagnostics = append(c.Diagnostics, d)
+}
+
+// CompileError is returned when a program fails to scan, parse or resolve,
+// and so never starts running. It holds every error that was reported.
+type CompileError struct {
+	Diagnostics []Diagnostic
+}
+
+// Error formats each diagnostic on its own line in the plain text format.
+func (e *CompileError) Error() string {
+	var b strings.Builder
+	sink := NewTextSink(&b)
+	for _, d := range e.Diagnostics {
+		sink.Report(d)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
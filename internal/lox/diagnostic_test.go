@@ -0,0 +1,91 @@
+package lox
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverity_String(t *testing.T) {
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "Severity(7)", Severity(7).String())
+}
+
+func TestPhase_String(t *testing.T) {
+	assert.Equal(t, "scan", PhaseScan.String())
+	assert.Equal(t, "parse", PhaseParse.String())
+	assert.Equal(t, "resolve", PhaseResolve.String())
+	assert.Equal(t, "runtime", PhaseRuntime.String())
+	assert.Equal(t, "Phase(9)", Phase(9).String())
+}
+
+func TestTextSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewTextSink(&buf)
+
+	sink.Report(Diagnostic{
+		Phase:   PhaseResolve,
+		Span:    Span{File: "a.lox", Line: 4, Column: 2, Text: "return"},
+		Message: "can't return from top-level code",
+	})
+	sink.Report(Diagnostic{
+		Phase:   PhaseRuntime,
+		Span:    Span{File: "a.lox", Line: 9, Column: 3},
+		Message: "undefined variable 'x'",
+	})
+
+	assert.Equal(t,
+		"a.lox:4:2: Error at 'return': can't return from top-level code\n"+
+			"undefined variable 'x'\n[a.lox:9:3]\n",
+		buf.String(),
+	)
+}
+
+func TestTextSink_CollapsesRepeatedFrames(t *testing.T) {
+	recursive := CallFrame{Function: "spin", Span: Span{File: "a.lox", Line: 2, Column: 14}}
+	trace := []CallFrame{{Function: "fail", Span: Span{File: "a.lox", Line: 5, Column: 1}}}
+	for range 6 {
+		trace = append(trace, recursive)
+	}
+	trace = append(trace, CallFrame{Span: Span{File: "a.lox", Line: 7, Column: 5}})
+
+	var buf bytes.Buffer
+	NewTextSink(&buf).Report(Diagnostic{
+		Phase:   PhaseRuntime,
+		Message: "stack overflow",
+		Trace:   trace,
+	})
+
+	assert.Equal(t,
+		"stack overflow\n"+
+			"[a.lox:5:1] in fail()\n"+
+			"[a.lox:2:14] in spin()\n"+
+			"[a.lox:2:14] in spin()\n"+
+			"[a.lox:2:14] in spin()\n"+
+			"[previous frame repeated 3 more times]\n"+
+			"[a.lox:7:5] in script\n",
+		buf.String(),
+	)
+}
+
+func TestDiagnosticCollector_CollectsAcrossPhases(t *testing.T) {
+	collector := &DiagnosticCollector{}
+	lox := &Lox{}
+	lox.SetDiagnosticSink(collector)
+
+	scanner := NewScanner("@", lox)
+	scanner.ScanTokens()
+	lox.hadError = false
+	_, _ = interpretSource(lox, "print nope;")
+
+	phases := []Phase{}
+	codes := []string{}
+	for _, d := range collector.Diagnostics {
+		phases = append(phases, d.Phase)
+		codes = append(codes, d.Code)
+	}
+	assert.Equal(t, []Phase{PhaseScan, PhaseRuntime}, phases)
+	assert.Equal(t, []string{CodeUnexpectedCharacter, CodeUndefinedVariable}, codes)
+}
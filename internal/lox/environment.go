@@ -0,0 +1,67 @@
+package lox
+
+// Environment stores variable bindings for a single lexical scope and links
+// to the scope that encloses it.
+type Environment struct {
+	values    map[string]any
+	enclosing *Environment
+}
+
+// NewEnvironment creates an empty scope nested inside enclosing. A nil
+// enclosing environment creates the global scope.
+func NewEnvironment(enclosing *Environment) *Environment {
+	return &Environment{
+		values:    map[string]any{},
+		enclosing: enclosing,
+	}
+}
+
+// Define binds name to value in this scope, replacing any existing binding.
+func (e *Environment) Define(name string, value any) {
+	e.values[name] = value
+}
+
+// Get looks up the value bound to name, walking outward through enclosing
+// scopes.
+func (e *Environment) Get(name Token) (any, error) {
+	if value, ok := e.values[name.Lexeme]; ok {
+		return value, nil
+	}
+	if e.enclosing != nil {
+		return e.enclosing.Get(name)
+	}
+	return nil, newRuntimeError(name, CodeUndefinedVariable, "undefined variable '%s'", name.Lexeme)
+}
+
+// Assign rebinds an existing variable, walking outward through enclosing
+// scopes. Assigning to a variable that was never declared is an error.
+func (e *Environment) Assign(name Token, value any) error {
+	if _, ok := e.values[name.Lexeme]; ok {
+		e.values[name.Lexeme] = value
+		return nil
+	}
+	if e.enclosing != nil {
+		return e.enclosing.Assign(name, value)
+	}
+	return newRuntimeError(name, CodeUndefinedVariable, "undefined variable '%s'", name.Lexeme)
+}
+
+// GetAt reads name from the scope exactly distance hops outward, as
+// computed by the Resolver.
+func (e *Environment) GetAt(distance int, name string) any {
+	return e.ancestor(distance).values[name]
+}
+
+// AssignAt rebinds name in the scope exactly distance hops outward, as
+// computed by the Resolver.
+func (e *Environment) AssignAt(distance int, name Token, value any) {
+	e.ancestor(distance).values[name.Lexeme] = value
+}
+
+func (e *Environment) ancestor(distance int) *Environment {
+	environment := e
+	for range distance {
+		environment = environment.enclosing
+	}
+	return environment
+}
@@ -0,0 +1,68 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironment_DefineAndGet(t *testing.T) {
+	env := NewEnvironment(nil)
+	env.Define("a", 1.0)
+
+	value, err := env.Get(NewToken(Identifier, "a", nil, 1))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+}
+
+func TestEnvironment_GetFromEnclosing(t *testing.T) {
+	outer := NewEnvironment(nil)
+	outer.Define("a", "outer")
+	inner := NewEnvironment(outer)
+
+	value, err := inner.Get(NewToken(Identifier, "a", nil, 1))
+
+	require.NoError(t, err)
+	assert.Equal(t, "outer", value)
+}
+
+func TestEnvironment_Shadowing(t *testing.T) {
+	outer := NewEnvironment(nil)
+	outer.Define("a", "outer")
+	inner := NewEnvironment(outer)
+	inner.Define("a", "inner")
+
+	innerValue, err := inner.Get(NewToken(Identifier, "a", nil, 1))
+	require.NoError(t, err)
+	outerValue, err := outer.Get(NewToken(Identifier, "a", nil, 1))
+	require.NoError(t, err)
+
+	assert.Equal(t, "inner", innerValue)
+	assert.Equal(t, "outer", outerValue)
+}
+
+func TestEnvironment_AssignToEnclosing(t *testing.T) {
+	outer := NewEnvironment(nil)
+	outer.Define("a", 1.0)
+	inner := NewEnvironment(outer)
+
+	err := inner.Assign(NewToken(Identifier, "a", nil, 1), 2.0)
+	require.NoError(t, err)
+
+	value, err := outer.Get(NewToken(Identifier, "a", nil, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, value)
+}
+
+func TestEnvironment_Undefined(t *testing.T) {
+	env := NewEnvironment(NewEnvironment(nil))
+	name := NewToken(Identifier, "missing", nil, 3)
+
+	_, err := env.Get(name)
+	assert.EqualError(t, err, "undefined variable 'missing'")
+
+	err = env.Assign(name, 1.0)
+	assert.EqualError(t, err, "undefined variable 'missing'")
+}
@@ -1,16 +1,36 @@
 package lox
 
 type Visitor interface {
+	VisitAssign(a *Assign)
 	VisitBinary(b Binary)
+	VisitCall(c Call)
+	VisitGet(g Get)
 	VisitGrouping(g Grouping)
 	VisitLiteral(l Literal)
+	VisitLogical(l Logical)
+	VisitSet(s Set)
+	VisitSuper(s *SuperExpr)
+	VisitThis(t *ThisExpr)
 	VisitUnary(u Unary)
+	VisitVariable(v *Variable)
 }
 
+// Expr is a node in the expression tree. Assign, SuperExpr, ThisExpr and
+// Variable are always handled by pointer so the resolver can key variable
+// bindings on the identity of the expression.
 type Expr interface {
 	Accept(v Visitor)
 }
 
+type Assign struct {
+	Name  Token
+	Value Expr
+}
+
+func (a *Assign) Accept(v Visitor) {
+	v.VisitAssign(a)
+}
+
 type Binary struct {
 	Left, Right Expr
 	Operator    Token
@@ -20,6 +40,25 @@ func (b Binary) Accept(v Visitor) {
 	v.VisitBinary(b)
 }
 
+type Call struct {
+	Callee    Expr
+	Paren     Token
+	Arguments []Expr
+}
+
+func (c Call) Accept(v Visitor) {
+	v.VisitCall(c)
+}
+
+type Get struct {
+	Object Expr
+	Name   Token
+}
+
+func (g Get) Accept(v Visitor) {
+	v.VisitGet(g)
+}
+
 type Grouping struct {
 	Expr Expr
 }
@@ -36,6 +75,42 @@ func (l Literal) Accept(v Visitor) {
 	v.VisitLiteral(l)
 }
 
+type Logical struct {
+	Left, Right Expr
+	Operator    Token
+}
+
+func (l Logical) Accept(v Visitor) {
+	v.VisitLogical(l)
+}
+
+type Set struct {
+	Object Expr
+	Name   Token
+	Value  Expr
+}
+
+func (s Set) Accept(v Visitor) {
+	v.VisitSet(s)
+}
+
+type SuperExpr struct {
+	Keyword Token
+	Method  Token
+}
+
+func (s *SuperExpr) Accept(v Visitor) {
+	v.VisitSuper(s)
+}
+
+type ThisExpr struct {
+	Keyword Token
+}
+
+func (t *ThisExpr) Accept(v Visitor) {
+	v.VisitThis(t)
+}
+
 type Unary struct {
 	Operator Token
 	Right    Expr
@@ -44,3 +119,11 @@ type Unary struct {
 func (u Unary) Accept(v Visitor) {
 	v.VisitUnary(u)
 }
+
+type Variable struct {
+	Name Token
+}
+
+func (vr *Variable) Accept(v Visitor) {
+	v.VisitVariable(vr)
+}
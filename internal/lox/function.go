@@ -0,0 +1,81 @@
+package lox
+
+import "fmt"
+
+// LoxCallable is implemented by every value that can be invoked with a
+// call expression.
+type LoxCallable interface {
+	Arity() int
+	Call(interpreter *Interpreter, arguments []any) (any, error)
+}
+
+// LoxFunction is a user-defined function together with the environment it
+// was declared in, which makes closures work.
+type LoxFunction struct {
+	declaration   FunctionStmt
+	closure       *Environment
+	isInitializer bool
+}
+
+// NewLoxFunction creates a function that closes over closure. Initializers
+// always return the instance they were bound to.
+func NewLoxFunction(declaration FunctionStmt, closure *Environment, isInitializer bool) *LoxFunction {
+	return &LoxFunction{
+		declaration:   declaration,
+		closure:       closure,
+		isInitializer: isInitializer,
+	}
+}
+
+// Bind returns a copy of the method whose closure defines "this" as
+// instance.
+func (f *LoxFunction) Bind(instance *LoxInstance) *LoxFunction {
+	environment := NewEnvironment(f.closure)
+	environment.Define("this", instance)
+	return NewLoxFunction(f.declaration, environment, f.isInitializer)
+}
+
+// Arity returns the number of parameters the function declares.
+func (f *LoxFunction) Arity() int {
+	return len(f.declaration.Params)
+}
+
+// Call binds arguments to the function's parameters in a fresh environment
+// and executes its body, yielding the value of any return statement.
+func (f *LoxFunction) Call(interpreter *Interpreter, arguments []any) (any, error) {
+	environment := NewEnvironment(f.closure)
+	for idx, param := range f.declaration.Params {
+		environment.Define(param.Lexeme, arguments[idx])
+	}
+
+	interpreter.executeBlock(f.declaration.Body, environment)
+	ret, ok := interpreter.err.(*returnValue)
+	if ok {
+		interpreter.err = nil
+	}
+	if interpreter.err != nil {
+		return nil, interpreter.err
+	}
+
+	if f.isInitializer {
+		return f.closure.GetAt(0, "this"), nil
+	}
+	if ok {
+		return ret.value, nil
+	}
+	return nil, nil
+}
+
+func (f *LoxFunction) String() string {
+	return fmt.Sprintf("<fn %s>", f.declaration.Name.Lexeme)
+}
+
+// returnValue unwinds the interpreter from a return statement to the
+// enclosing call, travelling through the same err field as runtime errors.
+type returnValue struct {
+	value any
+}
+
+func (r *returnValue) Error() string {
+	return "return outside of function"
+}
@@ -0,0 +1,21 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoxFunction(t *testing.T) {
+	declaration := FunctionStmt{
+		Name: NewToken(Identifier, "add", nil, 1),
+		Params: []Token{
+			NewToken(Identifier, "a", nil, 1),
+			NewToken(Identifier, "b", nil, 1),
+		},
+	}
+	fn := NewLoxFunction(declaration, NewEnvironment(nil), false)
+
+	assert.Equal(t, 2, fn.Arity())
+	assert.Equal(t, "<fn add>", fn.String())
+}
@@ -1,6 +1,7 @@
 package lox
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -8,29 +9,203 @@ type Interpreter struct {
 	value any
 	err   error
 	lox   *Lox
+
+	globals     *Environment
+	environment *Environment
+	locals      map[Expr]int
+	frames      []callFrame
+	steps       int
+	allocations int
+	stringBytes int
+
+	// ctx is held rather than passed along because visitor methods take no
+	// extra arguments. It is checked at loop back-edges and calls.
+	ctx context.Context
 }
 
 func NewInterpreter(lox *Lox) Interpreter {
-	return Interpreter{lox: lox}
+	globals := NewEnvironment(nil)
+	interpreter := Interpreter{
+		lox:         lox,
+		globals:     globals,
+		environment: globals,
+		locals:      map[Expr]int{},
+		ctx:         context.Background(),
+	}
+	for _, native := range prelude() {
+		globals.Define(native.Name, native)
+	}
+	interpreter.installBuiltins()
+	return interpreter
+}
+
+func (i *Interpreter) Interpret(statements []Stmt) error {
+	i.err = nil
+	i.frames = nil
+	for _, stmt := range statements {
+		stmt.Accept(i)
+		if i.err != nil {
+			return i.err
+		}
+	}
+	return nil
+}
+
+// Define binds name to value in the global environment.
+func (i *Interpreter) Define(name string, value any) {
+	i.globals.Define(name, value)
 }
 
-func (i *Interpreter) Interpret(e Expr) (any, error) {
+func (i *Interpreter) Evaluate(e Expr) (any, error) {
 	i.value = nil
 	i.err = nil
 	e.Accept(i)
 	return i.value, i.err
 }
 
+func (i *Interpreter) executeBlock(statements []Stmt, environment *Environment) {
+	previous := i.environment
+	defer func() { i.environment = previous }()
+
+	i.environment = environment
+	for _, stmt := range statements {
+		stmt.Accept(i)
+		if i.err != nil {
+			return
+		}
+	}
+}
+
+func (i *Interpreter) VisitBlockStmt(s BlockStmt) {
+	i.executeBlock(s.Statements, NewEnvironment(i.environment))
+}
+
+func (i *Interpreter) VisitClassStmt(s ClassStmt) {
+	var superclass *LoxClass
+	if s.Superclass != nil {
+		s.Superclass.Accept(i)
+		if i.err != nil {
+			return
+		}
+		var ok bool
+		superclass, ok = i.value.(*LoxClass)
+		if !ok {
+			i.error(newRuntimeError(
+				s.Superclass.Name, CodeSuperclassNotClass,
+				"superclass must be a class, got %s", TypeName(i.value),
+			), s.Superclass.Name)
+			return
+		}
+	}
+
+	i.environment.Define(s.Name.Lexeme, nil)
+
+	if superclass != nil {
+		i.environment = NewEnvironment(i.environment)
+		i.environment.Define("super", superclass)
+	}
+
+	methods := make(map[string]*LoxFunction, len(s.Methods))
+	for _, method := range s.Methods {
+		methods[method.Name.Lexeme] = NewLoxFunction(method, i.environment, method.Name.Lexeme == "init")
+	}
+
+	class := NewLoxClass(s.Name.Lexeme, superclass, methods)
+	if superclass != nil {
+		i.environment = i.environment.enclosing
+	}
+	if err := i.environment.Assign(s.Name, class); err != nil {
+		i.error(err, s.Name)
+	}
+}
+
+func (i *Interpreter) VisitExpressionStmt(s ExpressionStmt) {
+	s.Expr.Accept(i)
+}
+
+func (i *Interpreter) VisitFunctionStmt(s FunctionStmt) {
+	i.environment.Define(s.Name.Lexeme, NewLoxFunction(s, i.environment, false))
+}
+
+func (i *Interpreter) VisitIfStmt(s IfStmt) {
+	s.Condition.Accept(i)
+	if i.err != nil {
+		return
+	}
+	if isTruthy(i.value) {
+		s.ThenBranch.Accept(i)
+	} else if s.ElseBranch != nil {
+		s.ElseBranch.Accept(i)
+	}
+}
+
+func (i *Interpreter) VisitPrintStmt(s PrintStmt) {
+	s.Expr.Accept(i)
+	if i.err != nil {
+		return
+	}
+	_, _ = fmt.Fprintln(i.lox.output(), stringify(i.value))
+}
+
+func (i *Interpreter) VisitReturnStmt(s ReturnStmt) {
+	var value any
+	if s.Value != nil {
+		s.Value.Accept(i)
+		if i.err != nil {
+			return
+		}
+		value = i.value
+	}
+	i.err = &returnValue{value: value}
+}
+
+func (i *Interpreter) VisitVarStmt(s VarStmt) {
+	var value any
+	if s.Initializer != nil {
+		s.Initializer.Accept(i)
+		if i.err != nil {
+			return
+		}
+		value = i.value
+	}
+	i.environment.Define(s.Name.Lexeme, value)
+}
+
+func (i *Interpreter) VisitWhileStmt(s WhileStmt) {
+	for {
+		s.Condition.Accept(i)
+		if i.err != nil || !isTruthy(i.value) {
+			return
+		}
+		s.Body.Accept(i)
+		if i.err != nil || !i.checkContext(s.Keyword) || !i.step(s.Keyword) {
+			return
+		}
+	}
+}
+
+func (i *Interpreter) VisitAssign(a *Assign) {
+	a.Value.Accept(i)
+	if i.err != nil {
+		return
+	}
+	if distance, ok := i.locals[a]; ok {
+		i.environment.AssignAt(distance, a.Name, i.value)
+		return
+	}
+	if err := i.globals.Assign(a.Name, i.value); err != nil {
+		i.error(err, a.Name)
+	}
+}
+
 func (i *Interpreter) VisitBinary(b Binary) {
 	b.Left.Accept(i)
 	if i.err != nil {
-		i.error(i.err, b.Operator)
 		return
 	}
 	left := i.value
 	b.Right.Accept(i)
 	if i.err != nil {
-		i.error(i.err, b.Operator)
 		return
 	}
 	right := i.value
@@ -97,16 +272,149 @@ func (i *Interpreter) VisitBinary(b Binary) {
 		}
 		lStr, rStr, errStr := checkStrings(b.Operator, left, right)
 		if errStr == nil {
+			if err := i.allocateString(len(lStr) + len(rStr)); err != nil {
+				i.error(err, b.Operator)
+				return
+			}
 			i.value = lStr + rStr
 			return
 		}
 		i.error(
-			fmt.Errorf("operands to + must be two numbers or two strings"),
+			newRuntimeError(
+				b.Operator, CodeInvalidOperands,
+				"operands to + must be two numbers or two strings, got %s and %s",
+				TypeName(left), TypeName(right),
+			),
 			b.Operator,
 		)
 	}
 }
 
+func (i *Interpreter) VisitCall(c Call) {
+	c.Callee.Accept(i)
+	if i.err != nil {
+		return
+	}
+	callee := i.value
+
+	arguments := make([]any, 0, len(c.Arguments))
+	for _, argument := range c.Arguments {
+		argument.Accept(i)
+		if i.err != nil {
+			return
+		}
+		arguments = append(arguments, i.value)
+	}
+
+	function, ok := callee.(LoxCallable)
+	if !ok {
+		i.error(newRuntimeError(
+			c.Paren, CodeNotCallable,
+			"can only call functions and classes, got %s", TypeName(callee),
+		), c.Paren)
+		return
+	}
+	if len(arguments) != function.Arity() {
+		i.error(
+			newRuntimeError(
+				c.Paren, CodeArityMismatch,
+				"expected %d arguments but got %d", function.Arity(), len(arguments),
+			),
+			c.Paren,
+		)
+		return
+	}
+
+	if !i.checkContext(c.Paren) || !i.step(c.Paren) || !i.checkCallDepth(c.Paren) {
+		return
+	}
+
+	i.frames = append(i.frames, callFrame{function: callableName(function), call: c.Paren})
+	value, err := function.Call(i, arguments)
+	i.frames = i.frames[:len(i.frames)-1]
+	if i.err != nil {
+		// Already reported where it was raised inside the callee.
+		return
+	}
+	if err != nil {
+		i.error(err, c.Paren)
+		return
+	}
+	i.value = value
+}
+
+func (i *Interpreter) VisitGet(g Get) {
+	g.Object.Accept(i)
+	if i.err != nil {
+		return
+	}
+
+	object, ok := i.value.(Object)
+	if !ok {
+		i.error(newRuntimeError(
+			g.Name, CodeNotInstance,
+			"only instances have properties, got %s", TypeName(i.value),
+		), g.Name)
+		return
+	}
+	value, err := object.Get(g.Name)
+	if err != nil {
+		i.error(err, g.Name)
+		return
+	}
+	i.value = value
+}
+
+func (i *Interpreter) VisitSet(s Set) {
+	s.Object.Accept(i)
+	if i.err != nil {
+		return
+	}
+
+	object, ok := i.value.(Object)
+	if !ok {
+		i.error(newRuntimeError(
+			s.Name, CodeNotInstance,
+			"only instances have fields, got %s", TypeName(i.value),
+		), s.Name)
+		return
+	}
+
+	s.Value.Accept(i)
+	if i.err != nil {
+		return
+	}
+	if err := object.Set(s.Name, i.value); err != nil {
+		i.error(err, s.Name)
+	}
+}
+
+func (i *Interpreter) VisitSuper(s *SuperExpr) {
+	distance := i.locals[s]
+	superclass := i.environment.GetAt(distance, "super").(*LoxClass)
+	// "this" is always bound one scope inside the one holding "super".
+	object := i.environment.GetAt(distance-1, "this").(*LoxInstance)
+
+	method, ok := superclass.FindMethod(s.Method.Lexeme)
+	if !ok {
+		i.error(newRuntimeError(
+			s.Method, CodeUndefinedProperty,
+			"undefined property '%s'", s.Method.Lexeme,
+		), s.Method)
+		return
+	}
+	i.value = method.Bind(object)
+}
+
+func (i *Interpreter) VisitThis(t *ThisExpr) {
+	value, err := i.lookUpVariable(t.Keyword, t)
+	if err != nil {
+		i.error(err, t.Keyword)
+		return
+	}
+	i.value = value
+}
+
 func (i *Interpreter) VisitGrouping(g Grouping) {
 	g.Expr.Accept(i)
 }
@@ -124,10 +432,28 @@ func (i *Interpreter) VisitLiteral(l Literal) {
 	}
 }
 
+// VisitLogical short-circuits: the result is whichever operand decided the
+// outcome, not a coerced boolean.
+func (i *Interpreter) VisitLogical(l Logical) {
+	l.Left.Accept(i)
+	if i.err != nil {
+		return
+	}
+
+	if l.Operator.TokenType == Or {
+		if isTruthy(i.value) {
+			return
+		}
+	} else if !isTruthy(i.value) {
+		return
+	}
+
+	l.Right.Accept(i)
+}
+
 func (i *Interpreter) VisitUnary(u Unary) {
 	u.Right.Accept(i)
 	if i.err != nil {
-		i.error(i.err, u.Operator)
 		return
 	}
 
@@ -148,6 +474,27 @@ func (i *Interpreter) VisitUnary(u Unary) {
 	}
 }
 
+func (i *Interpreter) VisitVariable(v *Variable) {
+	value, err := i.lookUpVariable(v.Name, v)
+	if err != nil {
+		i.error(err, v.Name)
+		return
+	}
+	i.value = value
+}
+
+func (i *Interpreter) lookUpVariable(name Token, expr Expr) (any, error) {
+	if distance, ok := i.locals[expr]; ok {
+		return i.environment.GetAt(distance, name.Lexeme), nil
+	}
+	return i.globals.Get(name)
+}
+
+// resolve records the scope depth computed by the Resolver for expr.
+func (i *Interpreter) resolve(expr Expr, depth int) {
+	i.locals[expr] = depth
+}
+
 func isTruthy(b any) bool {
 	if b == nil {
 		return false
@@ -163,9 +510,10 @@ func checkNumber(operator Token, operand any) (float64, error) {
 	if ok {
 		return n, nil
 	}
-	return n, fmt.Errorf(
-		"operand to %s must be a number, got %v",
-		operator.Lexeme, operand,
+	return n, newRuntimeError(
+		operator, CodeOperandNotNumber,
+		"operand to %s must be a number, got %s",
+		operator.Lexeme, TypeName(operand),
 	)
 }
 
@@ -176,9 +524,10 @@ func checkNumbers(operator Token, left, right any) (float64, float64, error) {
 		return l, r, nil
 	}
 
-	return l, r, fmt.Errorf(
-		"operands to %s must be numbers, got %T, %T",
-		operator.Lexeme, left, right,
+	return l, r, newRuntimeError(
+		operator, CodeOperandsNotNumbers,
+		"operands to %s must be numbers, got %s and %s",
+		operator.Lexeme, TypeName(left), TypeName(right),
 	)
 }
 
@@ -189,13 +538,50 @@ func checkStrings(operator Token, left, right any) (string, string, error) {
 		return l, r, nil
 	}
 
-	return l, r, fmt.Errorf(
-		"operands to %s must be strings, got %T, %T",
-		operator.Lexeme, left, right,
+	return l, r, newRuntimeError(
+		operator, CodeInvalidOperands,
+		"operands to %s must be strings, got %s and %s",
+		operator.Lexeme, TypeName(left), TypeName(right),
 	)
 }
 
+// checkContext raises a runtime error with CodeCanceled at token if the
+// interpreter's context is done, reporting whether execution can continue.
+func (i *Interpreter) checkContext(token Token) bool {
+	err := i.ctx.Err()
+	if err == nil {
+		return true
+	}
+	i.error(&RuntimeError{
+		Err:   fmt.Errorf("execution stopped: %w", err),
+		Token: token,
+		Code:  CodeCanceled,
+	}, token)
+	return false
+}
+
+// error raises err as a runtime error at token, recording the current stack
+// trace. Errors that aren't already a RuntimeError are wrapped in one, and
+// those built outside the interpreter without a token are placed at token.
 func (i *Interpreter) error(err error, token Token) {
-	i.err = err
-	i.lox.runtimeError(err, token.Line)
+	runtimeErr := asRuntimeError(err, token)
+	if runtimeErr.Token.Line == 0 {
+		runtimeErr.Token = token
+	}
+	runtimeErr.Trace = i.stackTrace(runtimeErr.Token)
+	i.err = runtimeErr
+	i.lox.runtimeError(runtimeErr)
+}
+
+// stackTrace builds a trace for an error raised at token, walking outward
+// from the innermost active call to the top-level script.
+func (i *Interpreter) stackTrace(token Token) []CallFrame {
+	trace := make([]CallFrame, 0, len(i.frames)+1)
+	location := token
+	for idx := len(i.frames) - 1; idx >= 0; idx-- {
+		frame := i.frames[idx]
+		trace = append(trace, CallFrame{Function: frame.function, Span: i.lox.tokenSpan(location)})
+		location = frame.call
+	}
+	return append(trace, CallFrame{Span: i.lox.tokenSpan(location)})
 }
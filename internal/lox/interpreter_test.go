@@ -1,7 +1,11 @@
 package lox
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInterpreter_Literals(t *testing.T) {
@@ -42,14 +46,14 @@ func TestInterpreter_Literals(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			lox := &Lox{}
 			i := NewInterpreter(lox)
-			result, err := i.Interpret(tt.expr)
+			result, err := i.Evaluate(tt.expr)
 
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Interpret() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if result != tt.expected {
-				t.Errorf("Interpret() = %v, want %v", result, tt.expected)
+				t.Errorf("Evaluate() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -135,14 +139,14 @@ func TestInterpreter_UnaryExpressions(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			lox := &Lox{}
 			i := NewInterpreter(lox)
-			result, err := i.Interpret(tt.expr)
+			result, err := i.Evaluate(tt.expr)
 
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Interpret() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr && result != tt.expected {
-				t.Errorf("Interpret() = %v, want %v", result, tt.expected)
+				t.Errorf("Evaluate() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -239,14 +243,14 @@ func TestInterpreter_ArithmeticExpressions(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			lox := &Lox{}
 			i := NewInterpreter(lox)
-			result, err := i.Interpret(tt.expr)
+			result, err := i.Evaluate(tt.expr)
 
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Interpret() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr && result != tt.expected {
-				t.Errorf("Interpret() = %v, want %v", result, tt.expected)
+				t.Errorf("Evaluate() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -382,14 +386,14 @@ func TestInterpreter_ComparisonExpressions(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			lox := &Lox{}
 			i := NewInterpreter(lox)
-			result, err := i.Interpret(tt.expr)
+			result, err := i.Evaluate(tt.expr)
 
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Interpret() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr && result != tt.expected {
-				t.Errorf("Interpret() = %v, want %v", result, tt.expected)
+				t.Errorf("Evaluate() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -538,14 +542,14 @@ func TestInterpreter_ComplexNestedExpressions(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			lox := &Lox{}
 			i := NewInterpreter(lox)
-			result, err := i.Interpret(tt.expr)
+			result, err := i.Evaluate(tt.expr)
 
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Interpret() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr && result != tt.expected {
-				t.Errorf("Interpret() = %v, want %v", result, tt.expected)
+				t.Errorf("Evaluate() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -587,14 +591,14 @@ func TestInterpreter_Grouping(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			lox := &Lox{}
 			i := NewInterpreter(lox)
-			result, err := i.Interpret(tt.expr)
+			result, err := i.Evaluate(tt.expr)
 
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Interpret() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr && result != tt.expected {
-				t.Errorf("Interpret() = %v, want %v", result, tt.expected)
+				t.Errorf("Evaluate() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -771,11 +775,613 @@ func TestInterpreter_ErrorCases(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			lox := &Lox{}
 			i := NewInterpreter(lox)
-			_, err := i.Interpret(tt.expr)
+			_, err := i.Evaluate(tt.expr)
 
 			if err == nil {
-				t.Errorf("Interpret() expected error but got none")
+				t.Errorf("Evaluate() expected error but got none")
 			}
 		})
 	}
 }
+
+func TestInterpreter_Statements(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "print number",
+			source:   "print 1 + 2;",
+			expected: "3\n",
+		},
+		{
+			name:     "print string",
+			source:   "print \"hello\" + \" world\";",
+			expected: "hello world\n",
+		},
+		{
+			name:     "expression statement prints nothing",
+			source:   "1 + 2;",
+			expected: "",
+		},
+		{
+			name:     "multiple statements",
+			source:   "print 1;\nprint true;\n\"ignored\";\nprint 2 * 3;",
+			expected: "1\ntrue\n6\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := interpretSource(&Lox{}, tt.source)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestInterpreter_Statements_RuntimeErrorStopsExecution(t *testing.T) {
+	lox := &Lox{}
+	output, err := interpretSource(lox, "print 1;\nprint -\"a\";\nprint 2;")
+
+	assert.Error(t, err)
+	assert.True(t, lox.hadRuntimeError)
+	assert.Equal(t, "1\n", output[:2])
+	assert.NotContains(t, output, "2\n")
+}
+
+func TestInterpreter_Variables(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "global declaration",
+			source:   "var a = 1; print a;",
+			expected: "1\n",
+		},
+		{
+			name:     "uninitialized variable is nil",
+			source:   "var a; print a == nil;",
+			expected: "true\n",
+		},
+		{
+			name:     "redeclaring a global",
+			source:   "var a = 1; var a = 2; print a;",
+			expected: "2\n",
+		},
+		{
+			name:     "assignment",
+			source:   "var a = 1; a = a + 1; print a;",
+			expected: "2\n",
+		},
+		{
+			name:     "assignment is right associative and yields its value",
+			source:   "var a; var b; a = b = 3; print a; print b;",
+			expected: "3\n3\n",
+		},
+		{
+			name:     "block shadows outer variable",
+			source:   "var a = \"outer\"; { var a = \"inner\"; print a; } print a;",
+			expected: "inner\nouter\n",
+		},
+		{
+			name:     "block assigns outer variable",
+			source:   "var a = 1; { a = 2; } print a;",
+			expected: "2\n",
+		},
+		{
+			name: "nested blocks",
+			source: `var a = "global a";
+var b = "global b";
+{
+  var a = "outer a";
+  {
+    var b = "inner b";
+    print a;
+    print b;
+  }
+  print b;
+}
+print a;`,
+			expected: "outer a\ninner b\nglobal b\nglobal a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := interpretSource(&Lox{}, tt.source)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestInterpreter_UndefinedVariable(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "read undefined global",
+			source: "print 1;\nprint a;",
+		},
+		{
+			name:   "assign undefined global",
+			source: "print 1;\na = 1;",
+		},
+		{
+			name:   "read block variable after block ends",
+			source: "{ var a = 1; }\nprint a;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lox := &Lox{}
+			output, err := interpretSource(lox, tt.source)
+
+			assert.Error(t, err)
+			assert.True(t, lox.hadRuntimeError)
+			assert.Contains(t, output, "undefined variable 'a'\n[<stdin>:2:")
+		})
+	}
+}
+
+func TestInterpreter_ControlFlow(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "if true",
+			source:   "if (true) print 1;",
+			expected: "1\n",
+		},
+		{
+			name:     "if false without else",
+			source:   "if (false) print 1;",
+			expected: "",
+		},
+		{
+			name:     "if else",
+			source:   "if (nil) print 1; else print 2;",
+			expected: "2\n",
+		},
+		{
+			name:     "dangling else binds to nearest if",
+			source:   "if (true) if (false) print 1; else print 2;",
+			expected: "2\n",
+		},
+		{
+			name:     "while loop",
+			source:   "var i = 0; while (i < 3) { print i; i = i + 1; }",
+			expected: "0\n1\n2\n",
+		},
+		{
+			name:     "for loop",
+			source:   "for (var i = 0; i < 3; i = i + 1) print i;",
+			expected: "0\n1\n2\n",
+		},
+		{
+			name:     "for loop with only a condition",
+			source:   "var i = 0; for (; i < 2;) { print i; i = i + 1; }",
+			expected: "0\n1\n",
+		},
+		{
+			name:     "for loop scopes its variable",
+			source:   "var i = \"outer\"; for (var i = 0; i < 1; i = i + 1) {} print i;",
+			expected: "outer\n",
+		},
+		{
+			name:     "for loop with expression initializer",
+			source:   "var i; for (i = 0; i < 2; i = i + 1) {} print i;",
+			expected: "2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := interpretSource(&Lox{}, tt.source)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestInterpreter_LogicalOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "or returns first truthy operand",
+			source:   "print \"hi\" or 2;",
+			expected: "hi\n",
+		},
+		{
+			name:     "or returns right operand when left is falsey",
+			source:   "print nil or \"yes\";",
+			expected: "yes\n",
+		},
+		{
+			name:     "and returns first falsey operand",
+			source:   "print nil and \"never\";",
+			expected: "nil\n",
+		},
+		{
+			name:     "and returns right operand when left is truthy",
+			source:   "print 1 and 2;",
+			expected: "2\n",
+		},
+		{
+			name:     "or short-circuits",
+			source:   "var a = 1; true or (a = 2); print a;",
+			expected: "1\n",
+		},
+		{
+			name:     "and short-circuits",
+			source:   "var a = 1; false and (a = 2); print a;",
+			expected: "1\n",
+		},
+		{
+			name:     "and binds tighter than or",
+			source:   "print false and false or true;",
+			expected: "true\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := interpretSource(&Lox{}, tt.source)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestInterpreter_Functions(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "call with arguments",
+			source:   "fun add(a, b) { print a + b; } add(1, 2);",
+			expected: "3\n",
+		},
+		{
+			name:     "return value",
+			source:   "fun square(n) { return n * n; } print square(4);",
+			expected: "16\n",
+		},
+		{
+			name:     "implicit return is nil",
+			source:   "fun noop() {} print noop() == nil;",
+			expected: "true\n",
+		},
+		{
+			name:     "bare return is nil",
+			source:   "fun early() { return; print \"unreachable\"; } print early() == nil;",
+			expected: "true\n",
+		},
+		{
+			name:     "return from inside a loop",
+			source:   "fun first() { while (true) { for (var i = 0; ; i = i + 1) { if (i == 3) return i; } } } print first();",
+			expected: "3\n",
+		},
+		{
+			name:     "recursion",
+			source:   "fun fib(n) { if (n < 2) return n; return fib(n - 1) + fib(n - 2); } print fib(10);",
+			expected: "55\n",
+		},
+		{
+			name:     "functions are first-class",
+			source:   "fun hi() { print \"hi\"; } var f = hi; f(); print f;",
+			expected: "hi\n<fn hi>\n",
+		},
+		{
+			name: "closures capture their environment",
+			source: `fun makeCounter() {
+  var i = 0;
+  fun count() {
+    i = i + 1;
+    return i;
+  }
+  return count;
+}
+var counter = makeCounter();
+print counter();
+print counter();
+var other = makeCounter();
+print other();`,
+			expected: "1\n2\n1\n",
+		},
+		{
+			name:     "curried calls",
+			source:   "fun adder(a) { fun add(b) { return a + b; } return add; } print adder(1)(2);",
+			expected: "3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := interpretSource(&Lox{}, tt.source)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestInterpreter_CallErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		source           string
+		expectedErrorMsg string
+	}{
+		{
+			name:             "calling a non-callable",
+			source:           "\"not a function\"();",
+			expectedErrorMsg: "can only call functions and classes",
+		},
+		{
+			name:             "too few arguments",
+			source:           "fun f(a, b) {} f(1);",
+			expectedErrorMsg: "expected 2 arguments but got 1",
+		},
+		{
+			name:             "too many arguments",
+			source:           "fun f() {} f(1, 2);",
+			expectedErrorMsg: "expected 0 arguments but got 2",
+		},
+		{
+			name:             "error inside function body",
+			source:           "fun f() { return -\"a\"; } f();",
+			expectedErrorMsg: "operand to - must be a number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lox := &Lox{}
+			output, err := interpretSource(lox, tt.source)
+
+			assert.Error(t, err)
+			assert.True(t, lox.hadRuntimeError)
+			assert.Contains(t, output, tt.expectedErrorMsg)
+			assert.Equal(t, 1, strings.Count(output, tt.expectedErrorMsg), "error should be reported once")
+		})
+	}
+}
+
+func TestInterpreter_Classes(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "print class and instance",
+			source:   "class Bagel {} print Bagel; print Bagel();",
+			expected: "Bagel\nBagel instance\n",
+		},
+		{
+			name:     "fields",
+			source:   "class Box {} var b = Box(); b.value = 1; b.value = b.value + 1; print b.value;",
+			expected: "2\n",
+		},
+		{
+			name:     "set expression yields its value",
+			source:   "class Box {} var b = Box(); print b.value = \"v\";",
+			expected: "v\n",
+		},
+		{
+			name:     "methods",
+			source:   "class Bacon { eat() { print \"Crunch crunch crunch!\"; } } Bacon().eat();",
+			expected: "Crunch crunch crunch!\n",
+		},
+		{
+			name:     "this refers to the instance",
+			source:   "class Cake { taste() { print \"The \" + this.flavor + \" cake is delicious!\"; } } var cake = Cake(); cake.flavor = \"German chocolate\"; cake.taste();",
+			expected: "The German chocolate cake is delicious!\n",
+		},
+		{
+			name:     "bound methods remember this",
+			source:   "class Person { sayName() { print this.name; } } var jane = Person(); jane.name = \"Jane\"; var method = jane.sayName; method();",
+			expected: "Jane\n",
+		},
+		{
+			name:     "this in a closure inside a method",
+			source:   "class Thing { getCallback() { fun localFunction() { print this; } return localFunction; } } var callback = Thing().getCallback(); callback();",
+			expected: "Thing instance\n",
+		},
+		{
+			name:     "initializer",
+			source:   "class Point { init(x, y) { this.x = x; this.y = y; } } var p = Point(1, 2); print p.x + p.y;",
+			expected: "3\n",
+		},
+		{
+			name:     "calling init directly returns this",
+			source:   "class Foo { init() { print \"init\"; } } var foo = Foo(); print foo.init();",
+			expected: "init\ninit\nFoo instance\n",
+		},
+		{
+			name:     "early return in initializer returns this",
+			source:   "class Foo { init() { return; } } print Foo();",
+			expected: "Foo instance\n",
+		},
+		{
+			name:     "fields shadow methods",
+			source:   "class Foo { bar() { print \"method\"; } } var foo = Foo(); fun field() { print \"field\"; } foo.bar = field; foo.bar();",
+			expected: "field\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := interpretSource(&Lox{}, tt.source)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestInterpreter_ClassErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		source           string
+		expectedErrorMsg string
+	}{
+		{
+			name:             "undefined property",
+			source:           "class Foo {} Foo().bar;",
+			expectedErrorMsg: "undefined property 'bar'",
+		},
+		{
+			name:             "get on non-instance",
+			source:           "var a = 1; a.b;",
+			expectedErrorMsg: "only instances have properties",
+		},
+		{
+			name:             "set on non-instance",
+			source:           "\"str\".length = 1;",
+			expectedErrorMsg: "only instances have fields",
+		},
+		{
+			name:             "initializer arity",
+			source:           "class Point { init(x, y) {} } Point(1);",
+			expectedErrorMsg: "expected 2 arguments but got 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lox := &Lox{}
+			output, err := interpretSource(lox, tt.source)
+
+			assert.Error(t, err)
+			assert.True(t, lox.hadRuntimeError)
+			assert.Contains(t, output, tt.expectedErrorMsg)
+		})
+	}
+}
+
+func TestInterpreter_Inheritance(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "inherited method",
+			source:   "class Doughnut { cook() { print \"Fry until golden brown.\"; } } class BostonCream < Doughnut {} BostonCream().cook();",
+			expected: "Fry until golden brown.\n",
+		},
+		{
+			name: "super call",
+			source: `class Doughnut {
+  cook() {
+    print "Fry until golden brown.";
+  }
+}
+
+class BostonCream < Doughnut {
+  cook() {
+    super.cook();
+    print "Pipe full of custard and coat with chocolate.";
+  }
+}
+
+BostonCream().cook();`,
+			expected: "Fry until golden brown.\nPipe full of custard and coat with chocolate.\n",
+		},
+		{
+			name: "super binds to the superclass of the declaring class",
+			source: `class A {
+  method() {
+    print "A method";
+  }
+}
+
+class B < A {
+  method() {
+    print "B method";
+  }
+
+  test() {
+    super.method();
+  }
+}
+
+class C < B {}
+
+C().test();`,
+			expected: "A method\n",
+		},
+		{
+			name:     "inherited initializer",
+			source:   "class A { init(x) { this.x = x; } } class B < A {} print B(3).x;",
+			expected: "3\n",
+		},
+		{
+			name:     "super initializer",
+			source:   "class A { init(x) { this.x = x; } } class B < A { init() { super.init(4); } } print B().x;",
+			expected: "4\n",
+		},
+		{
+			name:     "bound super method",
+			source:   "class A { say() { print this.word; } } class B < A { get() { return super.say; } } var b = B(); b.word = \"hi\"; var f = b.get(); f();",
+			expected: "hi\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := interpretSource(&Lox{}, tt.source)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestInterpreter_InheritanceErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		source           string
+		expectedErrorMsg string
+	}{
+		{
+			name:             "superclass is not a class",
+			source:           "var NotAClass = \"so not a class\"; class Subclass < NotAClass {}",
+			expectedErrorMsg: "superclass must be a class",
+		},
+		{
+			name:             "undefined super method",
+			source:           "class A {} class B < A { m() { super.missing(); } } B().m();",
+			expectedErrorMsg: "undefined property 'missing'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lox := &Lox{}
+			output, err := interpretSource(lox, tt.source)
+
+			assert.Error(t, err)
+			assert.True(t, lox.hadRuntimeError)
+			assert.Contains(t, output, tt.expectedErrorMsg)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package lox
+
+import "fmt"
+
+// DefaultMaxCallDepth is the call depth allowed when Limits.MaxCallDepth is
+// zero. Unbounded recursion would otherwise exhaust the Go stack and crash
+// the host process.
+const DefaultMaxCallDepth = 10000
+
+// Limits bounds the resources a script may use, so untrusted code can be
+// run safely. Zero fields leave that resource unlimited, except
+// MaxCallDepth, which falls back to DefaultMaxCallDepth. Steps,
+// allocations and string bytes are counted afresh for each program run.
+type Limits struct {
+	// MaxCallDepth is how many calls may be active at once.
+	MaxCallDepth int
+	// MaxSteps is how many loop iterations and calls may run. Code
+	// between them is bounded by the length of the program.
+	MaxSteps int
+	// MaxAllocations is how many strings and lists may be built, by
+	// concatenation or the prelude, and instances created.
+	MaxAllocations int
+	// MaxStringBytes is how many bytes of string data those allocations
+	// may add up to. MaxAllocations alone can't bound memory, since each
+	// concatenation can double a string's size.
+	MaxStringBytes int
+}
+
+func (l Limits) maxCallDepth() int {
+	if l.MaxCallDepth > 0 {
+		return l.MaxCallDepth
+	}
+	return DefaultMaxCallDepth
+}
+
+// step counts a loop iteration or call at token against the step budget,
+// reporting whether execution can continue.
+func (i *Interpreter) step(token Token) bool {
+	i.steps++
+	limit := i.lox.limits.MaxSteps
+	if limit <= 0 || i.steps <= limit {
+		return true
+	}
+	i.error(newRuntimeError(token, CodeStepLimit, "step limit of %d exceeded", limit), token)
+	return false
+}
+
+// allocate counts a new string or instance against the allocation cap. The
+// error carries no token; it is placed where the allocation was requested.
+func (i *Interpreter) allocate() error {
+	i.allocations++
+	if limit := i.lox.limits.MaxAllocations; limit > 0 && i.allocations > limit {
+		return &RuntimeError{
+			Err:  fmt.Errorf("allocation limit of %d exceeded", limit),
+			Code: CodeAllocationLimit,
+		}
+	}
+	return nil
+}
+
+// allocateString counts a new string of size bytes against both the
+// allocation cap and the string byte budget. Callers check before building
+// the string, so an oversized one is never made.
+func (i *Interpreter) allocateString(size int) error {
+	if err := i.allocate(); err != nil {
+		return err
+	}
+	i.stringBytes += size
+	if limit := i.lox.limits.MaxStringBytes; limit > 0 && i.stringBytes > limit {
+		return &RuntimeError{
+			Err:  fmt.Errorf("string memory limit of %d bytes exceeded", limit),
+			Code: CodeAllocationLimit,
+		}
+	}
+	return nil
+}
+
+// checkCallDepth raises a stack overflow at token if another call would
+// exceed the maximum depth, reporting whether the call can go ahead.
+func (i *Interpreter) checkCallDepth(token Token) bool {
+	if len(i.frames) < i.lox.limits.maxCallDepth() {
+		return true
+	}
+	i.error(newRuntimeError(token, CodeStackOverflow, "stack overflow"), token)
+	return false
+}
@@ -0,0 +1,127 @@
+package lox
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name            string
+		limits          Limits
+		source          string
+		expectedCode    string
+		expectedMessage string
+		expectedLine    int
+	}{
+		{
+			name:            "unbounded recursion hits the default depth",
+			source:          "fun spin() {\n  return spin();\n}\nspin();",
+			expectedCode:    CodeStackOverflow,
+			expectedMessage: "stack overflow",
+			expectedLine:    2,
+		},
+		{
+			name:            "configured call depth",
+			limits:          Limits{MaxCallDepth: 3},
+			source:          "fun down(n) {\n  if (n > 0) down(n - 1);\n}\ndown(3);",
+			expectedCode:    CodeStackOverflow,
+			expectedMessage: "stack overflow",
+			expectedLine:    2,
+		},
+		{
+			name:            "loop iterations use steps",
+			limits:          Limits{MaxSteps: 5},
+			source:          "var i = 0;\nwhile (true) i = i + 1;",
+			expectedCode:    CodeStepLimit,
+			expectedMessage: "step limit of 5 exceeded",
+			expectedLine:    2,
+		},
+		{
+			name:            "calls use steps",
+			limits:          Limits{MaxSteps: 2},
+			source:          "fun f() {}\nf();\nf();\nf();",
+			expectedCode:    CodeStepLimit,
+			expectedMessage: "step limit of 2 exceeded",
+			expectedLine:    4,
+		},
+		{
+			name:            "string concatenation allocates",
+			limits:          Limits{MaxAllocations: 2},
+			source:          "var s = \"\";\nfor (var i = 0; i < 10; i = i + 1) {\n  s = s + \"x\";\n}",
+			expectedCode:    CodeAllocationLimit,
+			expectedMessage: "allocation limit of 2 exceeded",
+			expectedLine:    3,
+		},
+		{
+			name:            "string bytes are budgeted",
+			limits:          Limits{MaxAllocations: 100, MaxSteps: 1000, MaxStringBytes: 1 << 20},
+			source:          "var s = \"ab\";\nfor (var i = 0; i < 40; i = i + 1) {\n  s = s + s;\n}",
+			expectedCode:    CodeAllocationLimit,
+			expectedMessage: "string memory limit of 1048576 bytes exceeded",
+			expectedLine:    3,
+		},
+		{
+			name:            "prelude strings are budgeted",
+			limits:          Limits{MaxStringBytes: 10},
+			source:          "var s = \"abcdefgh\";\nupper(s);\nupper(s);",
+			expectedCode:    CodeAllocationLimit,
+			expectedMessage: "string memory limit of 10 bytes exceeded",
+			expectedLine:    3,
+		},
+		{
+			name:            "instances allocate",
+			limits:          Limits{MaxAllocations: 1},
+			source:          "class Point {}\nvar a = Point();\nvar b = Point();",
+			expectedCode:    CodeAllocationLimit,
+			expectedMessage: "allocation limit of 1 exceeded",
+			expectedLine:    3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &DiagnosticCollector{}
+			l := &Lox{}
+			l.SetDiagnosticSink(collector)
+			l.SetLimits(tt.limits)
+
+			_, err := l.Eval(context.Background(), tt.source)
+
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, tt.expectedCode, runtimeErr.Code)
+			assert.Equal(t, tt.expectedMessage, runtimeErr.Error())
+			assert.Equal(t, tt.expectedLine, runtimeErr.Token.Line)
+			require.Len(t, collector.Diagnostics, 1)
+		})
+	}
+}
+
+func TestLimits_WithinBudget(t *testing.T) {
+	l := &Lox{}
+	l.SetOutput(io.Discard, io.Discard)
+	l.SetLimits(Limits{MaxCallDepth: 5, MaxSteps: 20, MaxAllocations: 2})
+
+	source := `
+fun fib(n) {
+  if (n < 2) return n;
+  return fib(n - 1) + fib(n - 2);
+}
+fib(4);`
+
+	// Each run gets a fresh budget.
+	for range 3 {
+		value, err := l.Eval(context.Background(), source)
+		require.NoError(t, err)
+		assert.Equal(t, 3.0, value)
+	}
+
+	value, err := l.Eval(context.Background(), `"a" + "b" + "c";`)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", value)
+}
@@ -0,0 +1,529 @@
+package lox
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// errInterrupted is returned by a lineReader when the user abandons the
+// line being edited with Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// lineReader reads the REPL's input one line at a time, without the
+// trailing newline.
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// plainLineReader reads lines from input that isn't a terminal, such as a
+// pipe, where there's nothing to edit.
+type plainLineReader struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPlainLineReader(in io.Reader, out io.Writer) *plainLineReader {
+	return &plainLineReader{in: bufio.NewReader(in), out: out}
+}
+
+func (p *plainLineReader) ReadLine(prompt string) (string, error) {
+	_, _ = fmt.Fprint(p.out, prompt)
+	line, err := p.in.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// Control keys, as read from a terminal in raw mode.
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlG     = 7
+	keyCtrlH     = 8
+	keyTab       = 9
+	keyCtrlK     = 11
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlR     = 18
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyBackspace = 127
+)
+
+// Keys decoded from escape sequences. They live in the Unicode private use
+// area so they can't collide with typed characters.
+const (
+	keyUp rune = 0xE000 + iota
+	keyDown
+	keyRight
+	keyLeft
+	keyHome
+	keyEnd
+	keyDelete
+)
+
+// completer finds completions for the word that ends at pos in line,
+// returning where that word starts.
+type completer func(line []rune, pos int) (start int, candidates []string)
+
+// lineEditor reads lines from a terminal with cursor movement, history
+// navigation, reverse search (Ctrl-R) and tab completion.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	history  *history
+	complete completer
+
+	// makeRaw switches the terminal to raw mode for the length of a read
+	// and returns a function restoring it. It is nil when there's no
+	// terminal to switch, as in tests.
+	makeRaw func() (restore func(), err error)
+
+	prompt string
+	line   []rune
+	pos    int
+}
+
+func newLineEditor(in io.Reader, out io.Writer, history *history, complete completer) *lineEditor {
+	return &lineEditor{
+		in:       bufio.NewReader(in),
+		out:      out,
+		history:  history,
+		complete: complete,
+	}
+}
+
+// ReadLine edits a line until Enter is pressed, then records it in the
+// history. Ctrl-D on an empty line gives io.EOF and Ctrl-C errInterrupted.
+func (e *lineEditor) ReadLine(prompt string) (string, error) {
+	if e.makeRaw != nil {
+		if restore, err := e.makeRaw(); err == nil {
+			defer restore()
+		}
+	}
+
+	e.prompt, e.line, e.pos = prompt, nil, 0
+	browsing := len(e.history.entries)
+	var draft []rune
+	e.refresh()
+
+	var pending rune
+	for {
+		key := pending
+		pending = 0
+		if key == 0 {
+			var err error
+			if key, err = e.readKey(); err != nil {
+				return string(e.line), err
+			}
+		}
+
+		switch key {
+		case keyEnter, '\n':
+			e.write("\r\n")
+			line := string(e.line)
+			e.history.add(line)
+			return line, nil
+		case keyCtrlC:
+			e.write("^C\r\n")
+			return "", errInterrupted
+		case keyCtrlD:
+			if len(e.line) == 0 {
+				return "", io.EOF
+			}
+			e.deleteRange(e.pos, e.pos+1)
+		case keyDelete:
+			e.deleteRange(e.pos, e.pos+1)
+		case keyBackspace, keyCtrlH:
+			e.deleteRange(e.pos-1, e.pos)
+		case keyCtrlA, keyHome:
+			e.pos = 0
+		case keyCtrlE, keyEnd:
+			e.pos = len(e.line)
+		case keyCtrlB, keyLeft:
+			e.pos = max(e.pos-1, 0)
+		case keyCtrlF, keyRight:
+			e.pos = min(e.pos+1, len(e.line))
+		case keyCtrlK:
+			e.deleteRange(e.pos, len(e.line))
+		case keyCtrlU:
+			e.deleteRange(0, e.pos)
+		case keyCtrlW:
+			start := e.pos
+			for start > 0 && unicode.IsSpace(e.line[start-1]) {
+				start--
+			}
+			for start > 0 && !unicode.IsSpace(e.line[start-1]) {
+				start--
+			}
+			e.deleteRange(start, e.pos)
+		case keyCtrlP, keyUp:
+			if browsing == len(e.history.entries) {
+				draft = e.line
+			}
+			if browsing > 0 {
+				browsing--
+				e.setLine([]rune(e.history.entries[browsing]))
+			}
+		case keyCtrlN, keyDown:
+			if browsing < len(e.history.entries) {
+				browsing++
+				if browsing == len(e.history.entries) {
+					e.setLine(draft)
+				} else {
+					e.setLine([]rune(e.history.entries[browsing]))
+				}
+			}
+		case keyTab:
+			e.completeWord()
+		case keyCtrlR:
+			var err error
+			if pending, err = e.search(); err != nil {
+				return "", err
+			}
+		default:
+			if unicode.IsPrint(key) && (key < keyUp || key > keyDelete) {
+				e.insert(key)
+			}
+		}
+		e.refresh()
+	}
+}
+
+// readKey reads one keypress, decoding the escape sequences terminals send
+// for arrow and editing keys. Unrecognized sequences read as keyEscape.
+func (e *lineEditor) readKey() (rune, error) {
+	r, _, err := e.in.ReadRune()
+	if err != nil || r != keyEscape {
+		return r, err
+	}
+
+	introducer, _, err := e.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if introducer != '[' && introducer != 'O' {
+		return keyEscape, nil
+	}
+	code, _, err := e.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	switch code {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	case 'C':
+		return keyRight, nil
+	case 'D':
+		return keyLeft, nil
+	case 'H':
+		return keyHome, nil
+	case 'F':
+		return keyEnd, nil
+	}
+	if !isDigit(code) {
+		return keyEscape, nil
+	}
+
+	// ESC [ n ~ sequences, such as ESC [ 3 ~ for Delete.
+	digits := string(code)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if r == '~' {
+			break
+		}
+		if !isDigit(r) {
+			return keyEscape, nil
+		}
+		digits += string(r)
+	}
+	switch digits {
+	case "1", "7":
+		return keyHome, nil
+	case "4", "8":
+		return keyEnd, nil
+	case "3":
+		return keyDelete, nil
+	default:
+		return keyEscape, nil
+	}
+}
+
+// search runs a reverse incremental search through the history, started by
+// Ctrl-R. Typing narrows the search and Ctrl-R again finds an older match.
+// Ctrl-G or Ctrl-C abandons the search; any other key accepts the match
+// and is returned so the editor can act on it, letting Enter submit it.
+func (e *lineEditor) search() (rune, error) {
+	original, originalPos := e.line, e.pos
+	var query []rune
+	found := len(e.history.entries)
+	match := ""
+
+	find := func(from int) {
+		for idx := from; idx >= 0; idx-- {
+			if strings.Contains(e.history.entries[idx], string(query)) {
+				found, match = idx, e.history.entries[idx]
+				return
+			}
+		}
+	}
+
+	for {
+		e.write(fmt.Sprintf("\r(reverse-i-search)`%s': %s\x1b[K", string(query), match))
+		key, err := e.readKey()
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case key == keyCtrlR:
+			find(found - 1)
+		case key == keyBackspace || key == keyCtrlH:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				found, match = len(e.history.entries), ""
+				find(found - 1)
+			}
+		case key == keyCtrlG || key == keyCtrlC:
+			e.line, e.pos = original, originalPos
+			return 0, nil
+		case unicode.IsPrint(key) && (key < keyUp || key > keyDelete):
+			query = append(query, key)
+			find(min(found, len(e.history.entries)-1))
+		default:
+			if match != "" {
+				e.setLine([]rune(match))
+			} else {
+				e.line, e.pos = original, originalPos
+			}
+			return key, nil
+		}
+	}
+}
+
+// completeWord extends the word before the cursor as far as all its
+// completions agree, listing them when that doesn't add anything.
+func (e *lineEditor) completeWord() {
+	if e.complete == nil {
+		return
+	}
+	start, candidates := e.complete(e.line, e.pos)
+	if len(candidates) == 0 {
+		e.write("\a")
+		return
+	}
+
+	typed := len(e.line[start:e.pos])
+	common := []rune(commonPrefix(candidates))
+	if len(common) > typed {
+		for _, r := range common[typed:] {
+			e.insert(r)
+		}
+		return
+	}
+	if len(candidates) > 1 {
+		e.write("\r\n" + strings.Join(candidates, "  ") + "\r\n")
+	}
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func (e *lineEditor) insert(r rune) {
+	e.line = append(e.line[:e.pos], append([]rune{r}, e.line[e.pos:]...)...)
+	e.pos++
+}
+
+// deleteRange removes line[from:to], clamped to the line, leaving the
+// cursor where the text was.
+func (e *lineEditor) deleteRange(from, to int) {
+	from, to = max(from, 0), min(to, len(e.line))
+	if from >= to {
+		return
+	}
+	e.line = append(e.line[:from], e.line[to:]...)
+	e.pos = from
+}
+
+func (e *lineEditor) setLine(line []rune) {
+	e.line = append([]rune(nil), line...)
+	e.pos = len(e.line)
+}
+
+// refresh redraws the prompt and line, then moves the cursor into place.
+func (e *lineEditor) refresh() {
+	var b strings.Builder
+	b.WriteString("\r" + e.prompt + string(e.line) + "\x1b[K")
+	if back := len(e.line) - e.pos; back > 0 {
+		fmt.Fprintf(&b, "\x1b[%dD", back)
+	}
+	e.write(b.String())
+}
+
+func (e *lineEditor) write(s string) {
+	_, _ = io.WriteString(e.out, s)
+}
+
+// maxHistory is how many lines of history are kept.
+const maxHistory = 1000
+
+// history holds previously entered lines, oldest first, and appends new
+// ones to a file so they survive between sessions.
+type history struct {
+	entries []string
+	path    string
+}
+
+// historyPath is ~/.lox_history, or "" if there's no home directory, in
+// which case history only lasts for the session.
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".lox_history")
+}
+
+// loadHistory reads the history saved at path, keeping the last maxHistory
+// entries and rewriting the file to hold just those, since add only ever
+// appends to it. A missing or unreadable file starts an empty history.
+func loadHistory(path string) *history {
+	h := &history{path: path}
+	if path == "" {
+		return h
+	}
+	if contents, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(contents), "\n") {
+			if line != "" {
+				h.entries = append(h.entries, line)
+			}
+		}
+	}
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+		// Failing to trim the file only means reading more next time.
+		_ = os.WriteFile(path, []byte(strings.Join(h.entries, "\n")+"\n"), 0o600)
+	}
+	return h
+}
+
+// add records line unless it is blank or repeats the previous entry.
+// Failing to save it only loses it for future sessions.
+func (h *history) add(line string) {
+	if strings.TrimSpace(line) == "" ||
+		(len(h.entries) > 0 && h.entries[len(h.entries)-1] == line) {
+		return
+	}
+	h.entries = append(h.entries, line)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[1:]
+	}
+
+	if h.path == "" {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintln(f, line)
+	_ = f.Close()
+}
+
+// complete finds completions for the word ending at pos: keywords and
+// globals, or after a dot the properties of the instance before it.
+func (l *Lox) complete(line []rune, pos int) (int, []string) {
+	start := pos
+	for start > 0 && isAlphaNumeric(line[start-1]) {
+		start--
+	}
+	prefix := string(line[start:pos])
+
+	var names []string
+	if start > 0 && line[start-1] == '.' {
+		names = l.properties(line[:start-1])
+	} else {
+		for keyword := range keywords {
+			names = append(names, keyword)
+		}
+		for global := range l.session().globals.values {
+			names = append(names, global)
+		}
+	}
+
+	seen := map[string]bool{}
+	var candidates []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) && !seen[name] {
+			seen[name] = true
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	return start, candidates
+}
+
+// properties names the fields and methods of the instance that the dotted
+// path of globals and fields ending line refers to, such as "a.b" in
+// "print a.b". Completion never calls anything, so paths through method
+// calls aren't followed.
+func (l *Lox) properties(line []rune) []string {
+	start := len(line)
+	for start > 0 && (isAlphaNumeric(line[start-1]) || line[start-1] == '.') {
+		start--
+	}
+	path := strings.Split(string(line[start:]), ".")
+
+	value, ok := l.session().globals.values[path[0]]
+	for _, name := range path[1:] {
+		instance, isInstance := value.(*LoxInstance)
+		if !ok || !isInstance {
+			return nil
+		}
+		value, ok = instance.fields[name]
+	}
+	if !ok {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case *LoxInstance:
+		var names []string
+		for field := range v.fields {
+			names = append(names, field)
+		}
+		for class := v.class; class != nil; class = class.superclass {
+			for method := range class.methods {
+				names = append(names, method)
+			}
+		}
+		return names
+	case *LoxList:
+		return []string{"get", "length"}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+package lox
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	up    = "\x1b[A"
+	down  = "\x1b[B"
+	right = "\x1b[C"
+	left  = "\x1b[D"
+)
+
+func newTestEditor(input string, entries ...string) *lineEditor {
+	h := &history{entries: entries}
+	return newLineEditor(strings.NewReader(input), io.Discard, h, nil)
+}
+
+func TestLineEditor_ReadLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		history  []string
+		expected string
+	}{
+		{"typing", "print 1;\r", nil, "print 1;"},
+		{"newline also submits", "print 1;\n", nil, "print 1;"},
+		{"backspace", "prinx\x7ft;\r", nil, "print;"},
+		{"arrow keys move the cursor", "ac" + left + "b" + right + "d\r", nil, "abcd"},
+		{"home and end", "bc\x01a\x05d\r", nil, "abcd"},
+		{"home and end sequences", "bc\x1b[Ha\x1b[4~d\r", nil, "abcd"},
+		{"delete key", "abc\x01\x1b[3~\r", nil, "bc"},
+		{"ctrl-b and ctrl-f", "ac\x02b\x06d\r", nil, "abcd"},
+		{"kill to end", "abcd" + left + left + "\x0b\r", nil, "ab"},
+		{"kill to start", "abcd" + left + "\x15\r", nil, "d"},
+		{"delete word", "print value\x17x\r", nil, "print x"},
+		{"cursor stays in bounds", left + left + "a" + right + right + "b\r", nil, "ab"},
+		{"unicode", "é" + left + "ü\r", nil, "üé"},
+		{"previous history", up + "\r", []string{"one", "two"}, "two"},
+		{"older history", up + up + up + "\r", []string{"one", "two"}, "one"},
+		{"back to the draft", "dr" + up + up + down + down + "aft\r", []string{"one", "two"}, "draft"},
+		{"ctrl-p and ctrl-n", "\x10\x10\x0e\r", []string{"one", "two"}, "two"},
+		{"edit a history entry", up + "!\r", []string{"one"}, "one!"},
+		{"reverse search", "\x12on\r", []string{"one", "two", "none"}, "none"},
+		{"reverse search again", "\x12on\x12\r", []string{"one", "two", "none"}, "one"},
+		{"reverse search then edit", "\x12tw" + right + "!\r", []string{"one", "two"}, "two!"},
+		{"reverse search backspace", "\x12tx\x7f\r", []string{"one", "two"}, "two"},
+		{"reverse search cancelled", "keep\x12tw\x07\r", []string{"one", "two"}, "keep"},
+		{"reverse search without a match", "\x12zz\r", []string{"one"}, ""},
+		{"unknown escape sequences are ignored", "a\x1b[5~b\x1bxc\r", nil, "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editor := newTestEditor(tt.input, tt.history...)
+
+			line, err := editor.ReadLine("> ")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, line)
+		})
+	}
+}
+
+func TestLineEditor_EndOfInput(t *testing.T) {
+	_, err := newTestEditor("\x04").ReadLine("> ")
+	assert.ErrorIs(t, err, io.EOF)
+
+	line, err := newTestEditor("ab\x01\x04\r").ReadLine("> ")
+	require.NoError(t, err)
+	assert.Equal(t, "b", line)
+
+	line, err = newTestEditor("partial").ReadLine("> ")
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, "partial", line)
+
+	_, err = newTestEditor("abc\x03").ReadLine("> ")
+	assert.ErrorIs(t, err, errInterrupted)
+}
+
+func TestLineEditor_Redraws(t *testing.T) {
+	var out bytes.Buffer
+	editor := newLineEditor(strings.NewReader("ab"+left+"\r"), &out, &history{}, nil)
+
+	_, err := editor.ReadLine("> ")
+	require.NoError(t, err)
+	assert.Equal(t,
+		"\r> \x1b[K\r> a\x1b[K\r> ab\x1b[K\r> ab\x1b[K\x1b[1D\r\n",
+		out.String(),
+	)
+}
+
+func TestLineEditor_RecordsHistory(t *testing.T) {
+	editor := newTestEditor("one\rone\r  \rtwo\r")
+	for range 4 {
+		_, err := editor.ReadLine("> ")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"one", "two"}, editor.history.entries)
+}
+
+func TestLineEditor_Completion(t *testing.T) {
+	complete := func(line []rune, pos int) (int, []string) {
+		start := pos
+		for start > 0 && isAlphaNumeric(line[start-1]) {
+			start--
+		}
+		var candidates []string
+		for _, word := range []string{"print", "printer", "var"} {
+			if strings.HasPrefix(word, string(line[start:pos])) {
+				candidates = append(candidates, word)
+			}
+		}
+		return start, candidates
+	}
+
+	tests := []struct {
+		name           string
+		input          string
+		expected       string
+		expectedOutput string
+	}{
+		{"unique completion", "va\t\r", "var", ""},
+		{"common prefix", "pr\t\r", "print", ""},
+		{"lists ambiguous completions", "print\t\r", "print", "\r\nprint  printer\r\n"},
+		{"rings the bell without completions", "x\t\r", "x", "\a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			editor := newLineEditor(strings.NewReader(tt.input), &out, &history{}, complete)
+
+			line, err := editor.ReadLine("")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, line)
+			if tt.expectedOutput != "" {
+				assert.Contains(t, out.String(), tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestHistory_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lox_history")
+
+	h := loadHistory(path)
+	assert.Empty(t, h.entries)
+	h.add("var a = 1;")
+	h.add("print a;")
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "var a = 1;\nprint a;\n", string(contents))
+	assert.Equal(t, []string{"var a = 1;", "print a;"}, loadHistory(path).entries)
+
+	memory := loadHistory("")
+	memory.add("print 1;")
+	assert.Equal(t, []string{"print 1;"}, memory.entries)
+}
+
+func TestHistory_KeepsTheMostRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lox_history")
+	var lines strings.Builder
+	for i := range maxHistory + 10 {
+		lines.WriteString(strings.Repeat("x", i+1) + "\n")
+	}
+	require.NoError(t, os.WriteFile(path, []byte(lines.String()), 0o600))
+
+	h := loadHistory(path)
+	require.Len(t, h.entries, maxHistory)
+	assert.Equal(t, strings.Repeat("x", 11), h.entries[0])
+
+	// The file is trimmed to match, so it doesn't grow without bound.
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(h.entries, "\n")+"\n", string(contents))
+	assert.Equal(t, h.entries, loadHistory(path).entries)
+}
+
+func TestLox_complete(t *testing.T) {
+	l := &Lox{}
+	l.SetOutput(io.Discard, io.Discard)
+	_, err := l.Eval(context.Background(), `
+class Shape { area() { return 0; } }
+class Circle < Shape { init(r) { this.radius = r; } }
+var circle = Circle(2);
+var holder = Shape();
+holder.inner = circle;
+var parts = split("a b", " ");
+var printed = 1;
+`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		line          string
+		expectedStart int
+		expected      []string
+	}{
+		{"keywords and globals", "pri", 0, []string{"print", "printed"}},
+		{"globals mid-line", "var x = cir", 8, []string{"circle"}},
+		{"prelude natives", "sq", 0, []string{"sqrt"}},
+		{"instance fields and methods", "circle.", 7, []string{"area", "init", "radius"}},
+		{"filtered properties", "print circle.r", 13, []string{"radius"}},
+		{"nested fields", "holder.inner.ar", 13, []string{"area"}},
+		{"list properties", "parts.", 6, []string{"get", "length"}},
+		{"not an instance", "printed.", 8, nil},
+		{"unknown global", "missing.", 8, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := []rune(tt.line)
+			start, candidates := l.complete(line, len(line))
+			assert.Equal(t, tt.expectedStart, start)
+			assert.Equal(t, tt.expected, candidates)
+		})
+	}
+}
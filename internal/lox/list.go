@@ -0,0 +1,58 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoxList is a read-only sequence of values, as returned by split. Scripts
+// read its length property and fetch elements with its get method.
+type LoxList struct {
+	elements []any
+}
+
+// NewLoxList creates a list holding elements.
+func NewLoxList(elements []any) *LoxList {
+	return &LoxList{elements: elements}
+}
+
+// Get returns the list's length, or its get method bound to the list.
+func (l *LoxList) Get(name Token) (any, error) {
+	switch name.Lexeme {
+	case "length":
+		return float64(len(l.elements)), nil
+	case "get":
+		return &NativeFunction{
+			Name:  "get",
+			arity: 1,
+			fn: func(_ *Interpreter, arguments []any) (any, error) {
+				idx, err := integerArgument("get", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+				if idx < 0 || idx >= len(l.elements) {
+					return nil, &RuntimeError{
+						Err:  fmt.Errorf("index %d is out of range for a list of length %d", idx, len(l.elements)),
+						Code: CodeIndexOutOfRange,
+					}
+				}
+				return l.elements[idx], nil
+			},
+		}, nil
+	default:
+		return nil, newRuntimeError(name, CodeUndefinedProperty, "undefined property '%s'", name.Lexeme)
+	}
+}
+
+// Set always fails: lists can't be modified.
+func (l *LoxList) Set(name Token, _ any) error {
+	return newRuntimeError(name, CodeUndefinedProperty, "undefined property '%s'", name.Lexeme)
+}
+
+func (l *LoxList) String() string {
+	elements := make([]string, len(l.elements))
+	for idx, element := range l.elements {
+		elements[idx] = stringify(element)
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
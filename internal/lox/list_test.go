@@ -0,0 +1,43 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoxList(t *testing.T) {
+	list := NewLoxList([]any{"a", 1.0, nil})
+
+	assert.Equal(t, "[a, 1, nil]", list.String())
+	assert.Equal(t, "list", TypeName(list))
+
+	length, err := list.Get(NewToken(Identifier, "length", nil, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, length)
+
+	get, err := list.Get(NewToken(Identifier, "get", nil, 1))
+	require.NoError(t, err)
+	native, ok := get.(*NativeFunction)
+	require.True(t, ok)
+	assert.Equal(t, 1, native.Arity())
+
+	value, err := native.Call(nil, []any{1.0})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+
+	_, err = native.Call(nil, []any{3.0})
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeIndexOutOfRange, runtimeErr.Code)
+	assert.Equal(t, "index 3 is out of range for a list of length 3", runtimeErr.Error())
+
+	_, err = list.Get(NewToken(Identifier, "push", nil, 1))
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeUndefinedProperty, runtimeErr.Code)
+
+	err = list.Set(NewToken(Identifier, "length", nil, 1), 0.0)
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeUndefinedProperty, runtimeErr.Code)
+}
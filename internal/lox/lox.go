@@ -2,10 +2,13 @@
 package lox
 
 import (
-	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"time"
 )
 
 const (
@@ -17,31 +20,169 @@ const (
 	ExitInternalSoftware = 70
 )
 
+const usage = "Usage: go-lox [--timeout duration] [--allow capabilities] [script]"
+
 // Lox is the main interpreter struct that tracks error state.
 type Lox struct {
+	file            string
+	source          string
+	diagnostics     DiagnosticSink
+	stdout, stderr  io.Writer
+	interpreter     *Interpreter
+	timeout         time.Duration
+	limits          Limits
+	capabilities    Capabilities
+	errors          []Diagnostic
+	evaluating      bool
 	hadError        bool
 	hadRuntimeError bool
 }
 
-func (l *Lox) error(line int, message string) {
-	l.report(line, "", message)
+// SetDiagnosticSink routes every reported diagnostic to sink. By default
+// diagnostics are written to standard output with a snippet of the
+// offending source.
+func (l *Lox) SetDiagnosticSink(sink DiagnosticSink) {
+	l.diagnostics = sink
+}
+
+// SetOutput directs the output of print statements to stdout and, unless a
+// DiagnosticSink has been set, reported diagnostics to stderr. A nil writer
+// leaves the default, os.Stdout, in place for that stream.
+func (l *Lox) SetOutput(stdout, stderr io.Writer) {
+	l.stdout = stdout
+	l.stderr = stderr
+}
+
+// SetLimits bounds the resources scripts run by this Lox may use.
+func (l *Lox) SetLimits(limits Limits) {
+	l.limits = limits
 }
 
-func (l *Lox) runtimeError(err error, line int) {
-	fmt.Printf("%v\n[line %d]\n", err, line)
-	l.hadRuntimeError = true
+// SetCapabilities grants the capabilities that decide which builtins are
+// installed into globals, including those of a session already running.
+func (l *Lox) SetCapabilities(capabilities Capabilities) {
+	l.capabilities = capabilities
+	if l.interpreter != nil {
+		l.interpreter.installBuiltins()
+	}
 }
 
-func (l *Lox) report(line int, where, message string) {
-	fmt.Printf("[line %d] Error %s: %s\n", line, where, message)
-	l.hadError = true
+func (l *Lox) output() io.Writer {
+	if l.stdout != nil {
+		return l.stdout
+	}
+	return os.Stdout
+}
+
+func (l *Lox) errorOutput() io.Writer {
+	if l.stderr != nil {
+		return l.stderr
+	}
+	return os.Stdout
+}
+
+func (l *Lox) error(phase Phase, code string, span Span, message string) {
+	l.report(Diagnostic{
+		Severity: SeverityError,
+		Phase:    phase,
+		Span:     span,
+		Message:  message,
+		Code:     code,
+	}, nil)
+}
+
+func (l *Lox) runtimeError(err *RuntimeError) {
+	l.report(Diagnostic{
+		Severity: SeverityError,
+		Phase:    PhaseRuntime,
+		Span:     l.tokenSpan(err.Token),
+		Message:  err.Error(),
+		Code:     err.Code,
+		Trace:    err.Trace,
+	}, err.Token.Source)
+}
+
+// report sends d to the sink. source is where d's span points, or nil for
+// the source currently being run.
+func (l *Lox) report(d Diagnostic, source *Source) {
+	l.sink(source).Report(d)
+	if d.Severity != SeverityError {
+		return
+	}
+	if d.Phase == PhaseRuntime {
+		l.hadRuntimeError = true
+	} else {
+		l.hadError = true
+		l.errors = append(l.errors, d)
+	}
+}
+
+// sink returns where to report a diagnostic in source. The default sink is
+// built on every report, so its snippet comes from the right source, which
+// may be an earlier REPL entry or a loaded file, and a swapped os.Stdout is
+// respected.
+func (l *Lox) sink(source *Source) DiagnosticSink {
+	if l.diagnostics != nil {
+		return l.diagnostics
+	}
+	text := l.source
+	if source != nil {
+		text = source.Text
+	}
+	w := l.errorOutput()
+	f, ok := w.(*os.File)
+	return NewSnippetSink(w, text, ok && useColor(f))
+}
+
+// span locates text in source, or in the file being run if source is nil,
+// as it is for tokens the parser makes up.
+func (l *Lox) span(source *Source, line, column, start, end int, text string) Span {
+	file := l.file
+	if source != nil {
+		file = source.File
+	}
+	return Span{
+		File:   file,
+		Line:   line,
+		Column: column,
+		Start:  start,
+		End:    end,
+		Text:   text,
+	}
+}
+
+func (l *Lox) tokenSpan(token Token) Span {
+	return l.span(token.Source, token.Line, token.Column, token.Start, token.End, token.Lexeme)
 }
 
 // Run executes the Lox interpreter with the given command-line arguments.
 // If no arguments are provided, it starts an interactive REPL.
 // If one argument is provided, it interprets that file.
+// A --timeout flag stops a script, or each REPL entry, that runs too long,
+// and --allow grants capabilities such as fs.read or env to builtins, on
+// top of time, which is always granted so scripts have clock.
 // Returns an exit status code.
 func (l *Lox) Run(args []string) int {
+	flags := flag.NewFlagSet("go-lox", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.DurationVar(&l.timeout, "timeout", 0, "stop execution after this long")
+	// Standard Lox has clock, so the command line grants time up front.
+	allowed := NewCapabilities(CapabilityTime)
+	flags.Func("allow", "comma-separated capabilities to grant", func(list string) error {
+		capabilities, err := ParseCapabilities(list)
+		for capability := range capabilities {
+			allowed[capability] = true
+		}
+		return err
+	})
+	if err := flags.Parse(args); err != nil {
+		fmt.Println(err)
+		fmt.Println(usage)
+		return ExitUsage
+	}
+	args = flags.Args()
+	l.SetCapabilities(allowed)
+
 	var exitStatus int
 	switch len(args) {
 	case 0:
@@ -49,28 +190,14 @@ func (l *Lox) Run(args []string) int {
 	case 1:
 		exitStatus = l.runFile(args[0])
 	default:
-		fmt.Println("Usage: go-lox [script]")
+		fmt.Println(usage)
 		exitStatus = ExitUsage
 	}
 	return exitStatus
 }
 
-func (l *Lox) runPrompt() int {
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		fmt.Printf("> ")
-		input, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Println()
-			break
-		}
-		l.run(strings.TrimSpace(input))
-		l.hadError = false
-	}
-	return 0
-}
-
 func (l *Lox) runFile(filepath string) int {
+	l.file = filepath
 	if f, err := os.ReadFile(filepath); err == nil {
 		l.run(string(f))
 	}
@@ -84,20 +211,124 @@ func (l *Lox) runFile(filepath string) int {
 }
 
 func (l *Lox) run(input string) {
+	ctx, cancel := l.runContext()
+	defer cancel()
+
+	interpreter := NewInterpreter(l)
+	// Scripts only print what they ask to, so the value of a final
+	// expression is dropped. Every error has already been reported.
+	_, _, _ = l.execute(ctx, input, &interpreter)
+}
+
+// runContext bounds a single run from the command line by the --timeout
+// flag, if one was given.
+func (l *Lox) runContext() (context.Context, context.CancelFunc) {
+	if l.timeout > 0 {
+		return context.WithTimeout(context.Background(), l.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// errReentrantEval is returned when a native tries to run more source in the
+// session that is running it.
+var errReentrantEval = errors.New("can't evaluate source while a script is running")
+
+// Eval runs source in this Lox's session, so globals defined by earlier
+// calls stay visible, and returns the value of the final statement if it is
+// a bare expression. Errors found before the program runs are returned
+// together as a *CompileError; a failure while running is a *RuntimeError.
+// Every error is also reported to the DiagnosticSink.
+//
+// Once ctx is done the script stops at its next loop iteration or call
+// with a RuntimeError whose Code is CodeCanceled and which wraps ctx.Err().
+func (l *Lox) Eval(ctx context.Context, source string) (any, error) {
+	value, _, err := l.eval(ctx, source)
+	return value, err
+}
+
+// eval is Eval that also reports whether source ended in a bare expression,
+// which tells a nil value apart from no value at all.
+func (l *Lox) eval(ctx context.Context, source string) (any, bool, error) {
+	if l.evaluating {
+		return nil, false, errReentrantEval
+	}
+	l.evaluating = true
+	defer func() { l.evaluating = false }()
+
+	l.hadError = false
+	l.hadRuntimeError = false
+	return l.execute(ctx, source, l.session())
+}
+
+// Define binds name to value as a global in this Lox's session, for hosts
+// exposing natives and other values to scripts run with Eval.
+func (l *Lox) Define(name string, value any) {
+	l.session().Define(name, value)
+}
+
+func (l *Lox) session() *Interpreter {
+	if l.interpreter == nil {
+		interpreter := NewInterpreter(l)
+		l.interpreter = &interpreter
+	}
+	return l.interpreter
+}
+
+// EvalFile is Eval for the contents of the file at path, with diagnostics
+// reporting their location in that file.
+func (l *Lox) EvalFile(ctx context.Context, path string) (any, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	previous := l.file
+	defer func() { l.file = previous }()
+	l.file = path
+	return l.Eval(ctx, string(source))
+}
+
+// execute runs input with interpreter, returning the value of its final
+// statement and true when that statement is a bare expression.
+func (l *Lox) execute(ctx context.Context, input string, interpreter *Interpreter) (any, bool, error) {
+	l.source = input
+	l.errors = nil
 	scanner := NewScanner(input, l)
 	tokens := scanner.ScanTokens()
 
 	parser := NewParser(tokens, l)
-	expr, err := parser.Parse()
-	if err != nil || expr == nil {
-		return
+	statements, _ := parser.Parse()
+	if l.hadError {
+		return nil, false, &CompileError{Diagnostics: l.errors}
 	}
 
-	interpreter := NewInterpreter(l)
-	v, err := interpreter.Interpret(expr)
+	resolver := NewResolver(interpreter, l)
+	resolver.Resolve(statements)
+	if l.hadError {
+		return nil, false, &CompileError{Diagnostics: l.errors}
+	}
+
+	interpreter.ctx = ctx
+	defer func() { interpreter.ctx = context.Background() }()
+	interpreter.steps = 0
+	interpreter.allocations = 0
+	interpreter.stringBytes = 0
+
+	// Runtime errors are reported through l.runtimeError as they occur.
+	last := len(statements) - 1
+	if last < 0 {
+		return nil, false, nil
+	}
+	final, ok := statements[last].(ExpressionStmt)
+	if !ok {
+		return nil, false, interpreter.Interpret(statements)
+	}
+	if err := interpreter.Interpret(statements[:last]); err != nil {
+		return nil, false, err
+	}
+	value, err := interpreter.Evaluate(final.Expr)
 	if err != nil {
-		l.hadError = true
-		return
+		// The interpreter may hold a partial result from before the failure.
+		return nil, false, err
 	}
-	fmt.Printf("%v\n", v)
+	return value, true, nil
 }
@@ -1,9 +1,15 @@
 package lox
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,29 +20,72 @@ func TestLox_error(t *testing.T) {
 
 	require.False(t, l.hadError, "expected hadError to be false initially")
 
-	l.error(1, "test error")
+	l.error(PhaseScan, CodeUnexpectedCharacter, Span{Line: 1, Column: 1}, "test error")
 
 	assert.True(t, l.hadError, "expected hadError to be true after error")
 }
 
 func TestLox_report(t *testing.T) {
 	tests := []struct {
-		name    string
-		line    int
-		where   string
-		message string
+		name             string
+		diagnostic       Diagnostic
+		expectHadError   bool
+		expectHadRuntime bool
+		expectedOutput   string
 	}{
 		{
-			name:    "basic error",
-			line:    1,
-			where:   "",
-			message: "Unexpected token",
+			name: "scan error",
+			diagnostic: Diagnostic{
+				Phase:   PhaseScan,
+				Span:    Span{Line: 1, Column: 1, Text: "@"},
+				Message: "Unexpected character '@'",
+				Code:    CodeUnexpectedCharacter,
+			},
+			expectHadError: true,
+			expectedOutput: "<stdin>:1:1: Error: Unexpected character '@'\n",
 		},
 		{
-			name:    "error with location",
-			line:    5,
-			where:   "at 'foo'",
-			message: "Undefined variable",
+			name: "parse error at token",
+			diagnostic: Diagnostic{
+				Phase:   PhaseParse,
+				Span:    Span{Line: 5, Column: 12, Text: "foo"},
+				Message: "Undefined variable",
+				Code:    CodeSyntax,
+			},
+			expectHadError: true,
+			expectedOutput: "<stdin>:5:12: Error at 'foo': Undefined variable\n",
+		},
+		{
+			name: "parse error at end of a file",
+			diagnostic: Diagnostic{
+				Phase:   PhaseParse,
+				Span:    Span{File: "script.lox", Line: 3, Column: 7},
+				Message: "expect ';' after value",
+				Code:    CodeSyntax,
+			},
+			expectHadError: true,
+			expectedOutput: "script.lox:3:7: Error at end: expect ';' after value\n",
+		},
+		{
+			name: "runtime error",
+			diagnostic: Diagnostic{
+				Phase:   PhaseRuntime,
+				Span:    Span{File: "script.lox", Line: 2, Column: 5, Text: "-"},
+				Message: "operand to - must be a number",
+				Code:    CodeRuntime,
+			},
+			expectHadRuntime: true,
+			expectedOutput:   "operand to - must be a number\n[script.lox:2:5]\n",
+		},
+		{
+			name: "warning",
+			diagnostic: Diagnostic{
+				Severity: SeverityWarning,
+				Phase:    PhaseResolve,
+				Span:     Span{Line: 1, Column: 5, Text: "a"},
+				Message:  "unused variable",
+			},
+			expectedOutput: "<stdin>:1:5: Warning at 'a': unused variable\n",
 		},
 	}
 
@@ -44,15 +93,67 @@ func TestLox_report(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			l := &Lox{}
 
-			require.False(t, l.hadError, "expected hadError to be false initially")
+			output, err := captureOutput(func() error {
+				l.report(tt.diagnostic, nil)
+				return nil
+			})
 
-			l.report(tt.line, tt.where, tt.message)
-
-			assert.True(t, l.hadError, "expected hadError to be true after report")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectHadError, l.hadError)
+			assert.Equal(t, tt.expectHadRuntime, l.hadRuntimeError)
+			assert.Equal(t, tt.expectedOutput, output)
 		})
 	}
 }
 
+func TestLox_SetDiagnosticSink(t *testing.T) {
+	collector := &DiagnosticCollector{}
+	l := &Lox{}
+	l.SetDiagnosticSink(collector)
+
+	output, err := captureOutput(func() error {
+		l.run("print 1;\nvar = 2;\nprint -\"x\";")
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "", output, "nothing should run or print after a syntax error")
+	require.Len(t, collector.Diagnostics, 1)
+	assert.Equal(t, Diagnostic{
+		Severity: SeverityError,
+		Phase:    PhaseParse,
+		Span:     Span{Line: 2, Column: 5, Start: 13, End: 14, Text: "="},
+		Message:  "expect variable name",
+		Code:     CodeSyntax,
+	}, collector.Diagnostics[0])
+}
+
+func TestLox_runtimeError(t *testing.T) {
+	collector := &DiagnosticCollector{}
+	l := &Lox{file: "script.lox"}
+	l.SetDiagnosticSink(collector)
+
+	token := Token{TokenType: Minus, Lexeme: "-", Line: 2, Column: 5, Start: 14, End: 15}
+	trace := []CallFrame{{Span: Span{File: "script.lox", Line: 2, Column: 5}}}
+	l.runtimeError(&RuntimeError{
+		Err:   fmt.Errorf("operand to - must be a number"),
+		Token: token,
+		Code:  CodeOperandNotNumber,
+		Trace: trace,
+	})
+
+	assert.True(t, l.hadRuntimeError)
+	assert.False(t, l.hadError)
+	assert.Equal(t, []Diagnostic{{
+		Severity: SeverityError,
+		Phase:    PhaseRuntime,
+		Span:     Span{File: "script.lox", Line: 2, Column: 5, Start: 14, End: 15, Text: "-"},
+		Message:  "operand to - must be a number",
+		Code:     CodeOperandNotNumber,
+		Trace:    trace,
+	}}, collector.Diagnostics)
+}
+
 func TestLox_Run(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -70,7 +171,7 @@ func TestLox_Run(t *testing.T) {
 		{
 			name:               "single file arg",
 			args:               []string{"test.lox"},
-			fileContent:        "true == false",
+			fileContent:        "true == false;",
 			expectHadError:     false,
 			expectedExitStatus: 0,
 		},
@@ -119,12 +220,22 @@ func TestLox_runFile(t *testing.T) {
 		},
 		{
 			name:           "simple content",
-			content:        "3 + 4",
+			content:        "3 + 4;",
+			expectHadError: false,
+		},
+		{
+			name:           "multiple statements",
+			content:        "print 1;\nprint 2;",
 			expectHadError: false,
 		},
+		{
+			name:           "missing semicolon",
+			content:        "print 1",
+			expectHadError: true,
+		},
 		{
 			name:           "multiline content",
-			content:        "3 * 2 -\n 17",
+			content:        "3 * 2 -\n 17;",
 			expectHadError: false,
 		},
 	}
@@ -148,30 +259,304 @@ func TestLox_runFile(t *testing.T) {
 
 func TestLox_run(t *testing.T) {
 	tests := []struct {
-		name  string
-		input string
+		name     string
+		input    string
+		expected string
 	}{
 		{
-			name:  "empty input",
-			input: "",
+			name:     "empty input",
+			input:    "",
+			expected: "",
 		},
 		{
-			name:  "simple statement",
-			input: "3 + 1",
+			name:     "simple statement",
+			input:    "3 + 1;",
+			expected: "",
 		},
 		{
-			name:  "multiline input",
-			input: "3 + 1 / \n 3",
+			name:     "multiline input",
+			input:    "3 + 1 / \n 3;",
+			expected: "",
+		},
+		{
+			name:     "print statements",
+			input:    "print 1; nil;",
+			expected: "1\n",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
 			l := &Lox{}
+			l.SetOutput(&out, &out)
 			l.run(tt.input)
 
-			// Currently run() just prints, so we're just verifying it doesn't panic
+			// Unlike the REPL, scripts never echo the value of an expression.
+			assert.Equal(t, tt.expected, out.String())
 			assert.False(t, l.hadError, "expected hadError to remain false")
 		})
 	}
 }
+
+func TestLox_Eval(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "empty source",
+			source:   "",
+			expected: nil,
+		},
+		{
+			name:     "trailing expression",
+			source:   "var a = 2; a * 3;",
+			expected: 6.0,
+		},
+		{
+			name:     "trailing declaration",
+			source:   "var a = 2;",
+			expected: nil,
+		},
+		{
+			name:     "trailing call",
+			source:   "fun greet(name) { return \"hi \" + name; } greet(\"lox\");",
+			expected: "hi lox",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Lox{}
+			l.SetOutput(io.Discard, io.Discard)
+
+			value, err := l.Eval(context.Background(), tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestLox_Eval_PersistsGlobals(t *testing.T) {
+	var stdout bytes.Buffer
+	l := &Lox{}
+	l.SetOutput(&stdout, io.Discard)
+
+	_, err := l.Eval(context.Background(), "var count = 1; fun bump() { count = count + 1; }")
+	require.NoError(t, err)
+	_, err = l.Eval(context.Background(), "bump(); bump();")
+	require.NoError(t, err)
+	value, err := l.Eval(context.Background(), "print count; count;")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3.0, value)
+	assert.Equal(t, "3\n", stdout.String())
+}
+
+func TestLox_Eval_Errors(t *testing.T) {
+	var stderr bytes.Buffer
+	l := &Lox{}
+	l.SetOutput(io.Discard, &stderr)
+
+	_, err := l.Eval(context.Background(), "var a = ;\nprint 1 @;")
+	var compileErr *CompileError
+	require.ErrorAs(t, err, &compileErr)
+	require.Len(t, compileErr.Diagnostics, 2)
+	assert.Equal(t, PhaseScan, compileErr.Diagnostics[0].Phase)
+	assert.Equal(t, PhaseParse, compileErr.Diagnostics[1].Phase)
+	assert.Equal(t,
+		"<stdin>:2:9: Error: Unexpected character '@'\n<stdin>:1:9: Error at ';': unexpected token ';'",
+		compileErr.Error(),
+	)
+	assert.Contains(t, stderr.String(), "Unexpected character '@'")
+
+	_, err = l.Eval(context.Background(), "{ var a = 1; var a = 2; }")
+	require.ErrorAs(t, err, &compileErr)
+	assert.Equal(t, PhaseResolve, compileErr.Diagnostics[0].Phase)
+
+	// A failed call doesn't poison the session.
+	value, err := l.Eval(context.Background(), "1 + 1;")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, value)
+
+	_, err = l.Eval(context.Background(), "-nil;")
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeOperandNotNumber, runtimeErr.Code)
+
+	// A failed final expression yields no value, even part way through.
+	value, err = l.Eval(context.Background(), "len(missing);")
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeUndefinedVariable, runtimeErr.Code)
+	assert.Nil(t, value)
+}
+
+func TestLox_EvalFile(t *testing.T) {
+	var stderr bytes.Buffer
+	l := &Lox{}
+	l.SetOutput(io.Discard, &stderr)
+
+	path := filepath.Join(t.TempDir(), "script.lox")
+	require.NoError(t, os.WriteFile(path, []byte("var x = 1;\nfun f() { return -nil; }\nx + nil;"), 0644))
+
+	_, err := l.EvalFile(context.Background(), path)
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, path, runtimeErr.Trace[0].Span.File)
+
+	value, err := l.Eval(context.Background(), "x;")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+
+	// Functions from the file keep reporting errors against it.
+	stderr.Reset()
+	_, err = l.Eval(context.Background(), "\nf();")
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, path, runtimeErr.Trace[0].Span.File)
+	assert.Equal(t, 2, runtimeErr.Trace[0].Span.Line)
+	assert.Contains(t, stderr.String(), "2 | fun f() { return -nil; }")
+
+	_, err = l.EvalFile(context.Background(), filepath.Join(t.TempDir(), "missing.lox"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLox_Eval_Canceled(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		expectedLine int
+	}{
+		{
+			name:         "while loop",
+			source:       "var i = 0;\nwhile (true) i = i + 1;",
+			expectedLine: 2,
+		},
+		{
+			name:         "for loop",
+			source:       "for (;;) {}",
+			expectedLine: 1,
+		},
+		{
+			name:         "calls",
+			source:       "fun tick() {}\nwhile (true) tick();",
+			expectedLine: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &DiagnosticCollector{}
+			l := &Lox{}
+			l.SetDiagnosticSink(collector)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			_, err := l.Eval(ctx, tt.source)
+
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, CodeCanceled, runtimeErr.Code)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Equal(t, "execution stopped: context deadline exceeded", err.Error())
+			assert.Equal(t, tt.expectedLine, runtimeErr.Token.Line)
+			require.Len(t, collector.Diagnostics, 1)
+		})
+	}
+}
+
+func TestLox_Run_Timeout(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "loop.lox")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("while (true) {}"), 0644))
+
+	var exitStatus int
+	output, err := captureOutput(func() error {
+		exitStatus = (&Lox{}).Run([]string{"--timeout", "10ms", tmpFile})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ExitInternalSoftware, exitStatus)
+	assert.Contains(t, output, "execution stopped: context deadline exceeded")
+
+	output, err = captureOutput(func() error {
+		exitStatus = (&Lox{}).Run([]string{"--timeout", "soon", tmpFile})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ExitUsage, exitStatus)
+	assert.Contains(t, output, usage)
+}
+
+func TestLox_Run_DefaultGlobals(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "prelude.lox")
+	source := "print clock() > 0;\nprint str(len(\"abc\"));\nprint type(getenv);"
+	require.NoError(t, os.WriteFile(tmpFile, []byte(source), 0644))
+
+	var exitStatus int
+	output, err := captureOutput(func() error {
+		exitStatus = (&Lox{}).Run([]string{tmpFile})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ExitInternalSoftware, exitStatus)
+	assert.True(t, strings.HasPrefix(output, "true\n3\n"), output)
+	assert.Contains(t, output, "undefined variable 'getenv'")
+}
+
+func TestLox_Run_Allow(t *testing.T) {
+	t.Setenv("LOX_RUN_ALLOW", "set")
+	tmpFile := filepath.Join(t.TempDir(), "getenv.lox")
+	require.NoError(t, os.WriteFile(tmpFile, []byte(`print getenv("LOX_RUN_ALLOW");`), 0644))
+
+	tests := []struct {
+		name               string
+		args               []string
+		expectedExitStatus int
+		expectedOutput     string
+	}{
+		{
+			name:               "not granted",
+			args:               []string{tmpFile},
+			expectedExitStatus: ExitInternalSoftware,
+			expectedOutput:     "undefined variable 'getenv'",
+		},
+		{
+			name:               "granted",
+			args:               []string{"--allow", "env", tmpFile},
+			expectedExitStatus: 0,
+			expectedOutput:     "set\n",
+		},
+		{
+			name:               "granted across flags",
+			args:               []string{"--allow", "stdout", "--allow=fs.read,env", tmpFile},
+			expectedExitStatus: 0,
+			expectedOutput:     "set\n",
+		},
+		{
+			name:               "time alongside others",
+			args:               []string{"--allow=time,env", tmpFile},
+			expectedExitStatus: 0,
+			expectedOutput:     "set\n",
+		},
+		{
+			name:               "unknown capability",
+			args:               []string{"--allow", "network", tmpFile},
+			expectedExitStatus: ExitUsage,
+			expectedOutput:     `unknown capability "network"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var exitStatus int
+			output, err := captureOutput(func() error {
+				exitStatus = (&Lox{}).Run(tt.args)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedExitStatus, exitStatus)
+			assert.Contains(t, output, tt.expectedOutput)
+		})
+	}
+}
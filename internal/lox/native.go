@@ -0,0 +1,42 @@
+package lox
+
+import "fmt"
+
+// NativeFunction is a function implemented in Go and exposed to scripts as
+// an ordinary callable value.
+type NativeFunction struct {
+	Name  string
+	arity int
+	fn    func(interpreter *Interpreter, arguments []any) (any, error)
+
+	// capability is set on builtins, which are only installed when it
+	// has been granted.
+	capability Capability
+}
+
+// NewNativeFunction creates a native named name that takes arity
+// arguments. Errors returned by fn are raised as runtime errors at the call
+// site; a *RuntimeError keeps its Code.
+func NewNativeFunction(name string, arity int, fn func(arguments []any) (any, error)) *NativeFunction {
+	return &NativeFunction{
+		Name:  name,
+		arity: arity,
+		fn: func(_ *Interpreter, arguments []any) (any, error) {
+			return fn(arguments)
+		},
+	}
+}
+
+// Arity returns the number of arguments the native expects.
+func (n *NativeFunction) Arity() int {
+	return n.arity
+}
+
+// Call invokes the Go implementation with arguments.
+func (n *NativeFunction) Call(interpreter *Interpreter, arguments []any) (any, error) {
+	return n.fn(interpreter, arguments)
+}
+
+func (n *NativeFunction) String() string {
+	return fmt.Sprintf("<native fn %s>", n.Name)
+}
@@ -0,0 +1,70 @@
+package lox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNativeFunction(t *testing.T) {
+	native := NewNativeFunction("add", 2, func(arguments []any) (any, error) {
+		return arguments[0].(float64) + arguments[1].(float64), nil
+	})
+
+	assert.Equal(t, 2, native.Arity())
+	assert.Equal(t, "<native fn add>", native.String())
+	assert.Equal(t, "function", TypeName(native))
+
+	l := &Lox{}
+	l.SetOutput(io.Discard, io.Discard)
+	l.Define("add", native)
+	value, err := l.Eval(context.Background(), "add(1, add(2, 3));")
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, value)
+}
+
+func TestNativeFunction_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode string
+	}{
+		{
+			name:         "plain error",
+			err:          errors.New("boom"),
+			expectedCode: CodeRuntime,
+		},
+		{
+			name:         "runtime error without a token",
+			err:          &RuntimeError{Err: errors.New("boom"), Code: CodeInvalidArgument},
+			expectedCode: CodeInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &DiagnosticCollector{}
+			l := &Lox{}
+			l.SetDiagnosticSink(collector)
+			l.Define("fail", NewNativeFunction("fail", 0, func([]any) (any, error) {
+				return nil, tt.err
+			}))
+
+			_, err := l.Eval(context.Background(), "fun outer() {\n  fail();\n}\nouter();")
+
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, "boom", runtimeErr.Error())
+			assert.Equal(t, tt.expectedCode, runtimeErr.Code)
+			assert.Equal(t, 2, runtimeErr.Token.Line)
+			require.Len(t, runtimeErr.Trace, 2)
+			assert.Equal(t, "outer", runtimeErr.Trace[0].Function)
+			assert.Equal(t, 2, runtimeErr.Trace[0].Span.Line)
+			require.Len(t, collector.Diagnostics, 1)
+		})
+	}
+}
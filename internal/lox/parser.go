@@ -3,13 +3,48 @@ package lox
 import (
 	"fmt"
 	"slices"
+	"strings"
 )
 
+// maxArguments is the largest number of parameters or call arguments a
+// function may have.
+const maxArguments = 255
+
+// maxNestingDepth bounds how deeply statements and expressions may nest, so
+// hostile input is a syntax error rather than a Go stack overflow.
+const maxNestingDepth = 1000
+
+// ParseError is a single syntax error found while parsing.
+type ParseError struct {
+	Token   Token
+	Message string
+}
+
+func (e ParseError) Error() string {
+	return e.Message
+}
+
+// ParseErrors collects every syntax error found in one call to Parse.
+type ParseErrors []ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for idx, err := range e {
+		messages[idx] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
 type Parser struct {
 	tokens []Token
 	lox    *Lox
+	errors ParseErrors
 
 	current int
+	depth   int
+	// tooDeep is set once nesting passes maxNestingDepth, after which
+	// parsing stops rather than reporting an error at every level.
+	tooDeep bool
 }
 
 func NewParser(tokens []Token, lox *Lox) Parser {
@@ -19,15 +54,418 @@ func NewParser(tokens []Token, lox *Lox) Parser {
 	}
 }
 
-func (p *Parser) Parse() (Expr, error) {
-	if p.isAtEnd() {
-		return nil, nil
+// Parse parses a whole program. After a syntax error the parser
+// resynchronizes at the next statement boundary and keeps going, so every
+// error is reported, unless the input nests too deeply to parse at all. If
+// any were found they are returned as ParseErrors.
+func (p *Parser) Parse() ([]Stmt, error) {
+	p.errors = nil
+	p.tooDeep = false
+	statements := []Stmt{}
+	for !p.isAtEnd() {
+		stmt, err := p.Declaration()
+		if p.tooDeep {
+			// What follows is likely just as deep; one error is enough.
+			break
+		}
+		if err != nil {
+			continue
+		}
+		statements = append(statements, stmt)
+	}
+
+	if len(p.errors) > 0 {
+		return nil, p.errors
+	}
+	return statements, nil
+}
+
+// Declaration parses a single declaration or statement. On a syntax error it
+// skips ahead to the start of the next statement before returning the error.
+func (p *Parser) Declaration() (Stmt, error) {
+	stmt, err := p.declaration()
+	if err != nil {
+		p.synchronize()
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (p *Parser) declaration() (Stmt, error) {
+	switch {
+	case p.match(Class):
+		return p.ClassDeclaration()
+	case p.match(Fun):
+		return p.Function("function")
+	case p.match(Var):
+		return p.VarDeclaration()
+	}
+	return p.Statement()
+}
+
+func (p *Parser) ClassDeclaration() (Stmt, error) {
+	if err := p.consume(Identifier, "expect class name"); err != nil {
+		return nil, err
+	}
+	name := p.previous()
+
+	var superclass *Variable
+	if p.match(Less) {
+		if err := p.consume(Identifier, "expect superclass name"); err != nil {
+			return nil, err
+		}
+		superclass = &Variable{Name: p.previous()}
+	}
+
+	if err := p.consume(LeftBrace, "expect '{' before class body"); err != nil {
+		return nil, err
+	}
+	methods := []FunctionStmt{}
+	for !p.check(RightBrace) && !p.isAtEnd() {
+		method, err := p.Function("method")
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+	if err := p.consume(RightBrace, "expect '}' after class body"); err != nil {
+		return nil, err
 	}
-	return p.Expression()
+	return ClassStmt{Name: name, Superclass: superclass, Methods: methods}, nil
+}
+
+// Function parses a function's name, parameter list and body. kind is used
+// in error messages to describe what is being declared.
+func (p *Parser) Function(kind string) (FunctionStmt, error) {
+	// A function's body is parsed as a block directly, without going
+	// through Statement, so it needs its own nesting check.
+	leave, err := p.nest()
+	if err != nil {
+		return FunctionStmt{}, err
+	}
+	defer leave()
+
+	if err := p.consume(Identifier, "expect "+kind+" name"); err != nil {
+		return FunctionStmt{}, err
+	}
+	name := p.previous()
+
+	if err := p.consume(LeftParen, "expect '(' after "+kind+" name"); err != nil {
+		return FunctionStmt{}, err
+	}
+	params := []Token{}
+	if !p.check(RightParen) {
+		for {
+			if len(params) >= maxArguments {
+				p.error(p.peek(), fmt.Sprintf("can't have more than %d parameters", maxArguments))
+			}
+			if err := p.consume(Identifier, "expect parameter name"); err != nil {
+				return FunctionStmt{}, err
+			}
+			params = append(params, p.previous())
+			if !p.match(Comma) {
+				break
+			}
+		}
+	}
+	if err := p.consume(RightParen, "expect ')' after parameters"); err != nil {
+		return FunctionStmt{}, err
+	}
+
+	if err := p.consume(LeftBrace, "expect '{' before "+kind+" body"); err != nil {
+		return FunctionStmt{}, err
+	}
+	body, err := p.Block()
+	if err != nil {
+		return FunctionStmt{}, err
+	}
+	return FunctionStmt{Name: name, Params: params, Body: body}, nil
+}
+
+func (p *Parser) VarDeclaration() (Stmt, error) {
+	if err := p.consume(Identifier, "expect variable name"); err != nil {
+		return nil, err
+	}
+	name := p.previous()
+
+	var initializer Expr
+	if p.match(Equal) {
+		var err error
+		initializer, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.consume(Semicolon, "expect ';' after variable declaration"); err != nil {
+		return nil, err
+	}
+	return VarStmt{Name: name, Initializer: initializer}, nil
+}
+
+func (p *Parser) Statement() (Stmt, error) {
+	leave, err := p.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	switch {
+	case p.match(For):
+		return p.ForStatement()
+	case p.match(If):
+		return p.IfStatement()
+	case p.match(Print):
+		return p.PrintStatement()
+	case p.match(Return):
+		return p.ReturnStatement()
+	case p.match(While):
+		return p.WhileStatement()
+	case p.match(LeftBrace):
+		statements, err := p.Block()
+		if err != nil {
+			return nil, err
+		}
+		return BlockStmt{Statements: statements}, nil
+	}
+	return p.ExpressionStatement()
+}
+
+func (p *Parser) Block() ([]Stmt, error) {
+	statements := []Stmt{}
+	for !p.check(RightBrace) && !p.isAtEnd() {
+		stmt, err := p.Declaration()
+		if err != nil {
+			if p.tooDeep {
+				return nil, err
+			}
+			// Already recorded; keep parsing the rest of the block.
+			continue
+		}
+		statements = append(statements, stmt)
+	}
+
+	if err := p.consume(RightBrace, "expect '}' after block"); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+// ForStatement parses a C-style for loop and desugars it into a while loop
+// wrapped in blocks for the initializer and increment clauses.
+func (p *Parser) ForStatement() (Stmt, error) {
+	keyword := p.previous()
+	if err := p.consume(LeftParen, "expect '(' after 'for'"); err != nil {
+		return nil, err
+	}
+
+	var initializer Stmt
+	var err error
+	switch {
+	case p.match(Semicolon):
+		// No initializer clause.
+	case p.match(Var):
+		initializer, err = p.VarDeclaration()
+	default:
+		initializer, err = p.ExpressionStatement()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var condition Expr
+	if !p.check(Semicolon) {
+		condition, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := p.consume(Semicolon, "expect ';' after loop condition"); err != nil {
+		return nil, err
+	}
+
+	var increment Expr
+	if !p.check(RightParen) {
+		increment, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := p.consume(RightParen, "expect ')' after for clauses"); err != nil {
+		return nil, err
+	}
+
+	body, err := p.Statement()
+	if err != nil {
+		return nil, err
+	}
+
+	if increment != nil {
+		body = BlockStmt{Statements: []Stmt{body, ExpressionStmt{Expr: increment}}}
+	}
+	if condition == nil {
+		condition = Literal{Value: NewToken(True, "true", nil, p.previous().Line)}
+	}
+	body = WhileStmt{Keyword: keyword, Condition: condition, Body: body}
+	if initializer != nil {
+		body = BlockStmt{Statements: []Stmt{initializer, body}}
+	}
+	return body, nil
+}
+
+func (p *Parser) IfStatement() (Stmt, error) {
+	if err := p.consume(LeftParen, "expect '(' after 'if'"); err != nil {
+		return nil, err
+	}
+	condition, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.consume(RightParen, "expect ')' after if condition"); err != nil {
+		return nil, err
+	}
+
+	thenBranch, err := p.Statement()
+	if err != nil {
+		return nil, err
+	}
+	var elseBranch Stmt
+	if p.match(Else) {
+		elseBranch, err = p.Statement()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return IfStmt{Condition: condition, ThenBranch: thenBranch, ElseBranch: elseBranch}, nil
+}
+
+func (p *Parser) PrintStatement() (Stmt, error) {
+	value, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.consume(Semicolon, "expect ';' after value"); err != nil {
+		return nil, err
+	}
+	return PrintStmt{Expr: value}, nil
+}
+
+func (p *Parser) ReturnStatement() (Stmt, error) {
+	keyword := p.previous()
+	var value Expr
+	if !p.check(Semicolon) {
+		var err error
+		value, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.consume(Semicolon, "expect ';' after return value"); err != nil {
+		return nil, err
+	}
+	return ReturnStmt{Keyword: keyword, Value: value}, nil
+}
+
+func (p *Parser) WhileStatement() (Stmt, error) {
+	keyword := p.previous()
+	if err := p.consume(LeftParen, "expect '(' after 'while'"); err != nil {
+		return nil, err
+	}
+	condition, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.consume(RightParen, "expect ')' after condition"); err != nil {
+		return nil, err
+	}
+
+	body, err := p.Statement()
+	if err != nil {
+		return nil, err
+	}
+	return WhileStmt{Keyword: keyword, Condition: condition, Body: body}, nil
+}
+
+func (p *Parser) ExpressionStatement() (Stmt, error) {
+	expr, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.consume(Semicolon, "expect ';' after expression"); err != nil {
+		return nil, err
+	}
+	return ExpressionStmt{Expr: expr}, nil
 }
 
 func (p *Parser) Expression() (Expr, error) {
-	return p.Equality()
+	return p.Assignment()
+}
+
+func (p *Parser) Assignment() (Expr, error) {
+	leave, err := p.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	expr, err := p.Or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(Equal) {
+		equals := p.previous()
+		value, err := p.Assignment()
+		if err != nil {
+			return nil, err
+		}
+
+		switch target := expr.(type) {
+		case *Variable:
+			return &Assign{Name: target.Name, Value: value}, nil
+		case Get:
+			return Set{Object: target.Object, Name: target.Name, Value: value}, nil
+		}
+
+		// Report but don't unwind: the parser isn't in a confused state.
+		p.error(equals, "invalid assignment target")
+	}
+	return expr, nil
+}
+
+func (p *Parser) Or() (Expr, error) {
+	expr, err := p.And()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(Or) {
+		operator := p.previous()
+		right, err := p.And()
+		if err != nil {
+			return nil, err
+		}
+		expr = Logical{Left: expr, Operator: operator, Right: right}
+	}
+	return expr, nil
+}
+
+func (p *Parser) And() (Expr, error) {
+	expr, err := p.Equality()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(And) {
+		operator := p.previous()
+		right, err := p.Equality()
+		if err != nil {
+			return nil, err
+		}
+		expr = Logical{Left: expr, Operator: operator, Right: right}
+	}
+	return expr, nil
 }
 
 func (p *Parser) Equality() (Expr, error) {
@@ -100,6 +538,12 @@ func (p *Parser) Factor() (Expr, error) {
 
 func (p *Parser) Unary() (Expr, error) {
 	if p.match(Bang, Minus) {
+		leave, err := p.nest()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+
 		operator := p.previous()
 		right, err := p.Unary()
 		if err != nil {
@@ -108,7 +552,55 @@ func (p *Parser) Unary() (Expr, error) {
 		return Unary{Operator: operator, Right: right}, nil
 	}
 
-	return p.Primary()
+	return p.Call()
+}
+
+func (p *Parser) Call() (Expr, error) {
+	expr, err := p.Primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.match(LeftParen):
+			expr, err = p.finishCall(expr)
+			if err != nil {
+				return nil, err
+			}
+		case p.match(Dot):
+			if err := p.consume(Identifier, "expect property name after '.'"); err != nil {
+				return nil, err
+			}
+			expr = Get{Object: expr, Name: p.previous()}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *Parser) finishCall(callee Expr) (Expr, error) {
+	arguments := []Expr{}
+	if !p.check(RightParen) {
+		for {
+			if len(arguments) >= maxArguments {
+				p.error(p.peek(), fmt.Sprintf("can't have more than %d arguments", maxArguments))
+			}
+			argument, err := p.Expression()
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, argument)
+			if !p.match(Comma) {
+				break
+			}
+		}
+	}
+
+	if err := p.consume(RightParen, "expect ')' after arguments"); err != nil {
+		return nil, err
+	}
+	return Call{Callee: callee, Paren: p.previous(), Arguments: arguments}, nil
 }
 
 func (p *Parser) Primary() (Expr, error) {
@@ -116,28 +608,48 @@ func (p *Parser) Primary() (Expr, error) {
 	switch {
 	case p.match(False, True, Nil, Number, String):
 		expr = Literal{Value: p.previous()}
+	case p.match(Super):
+		keyword := p.previous()
+		if err := p.consume(Dot, "expect '.' after 'super'"); err != nil {
+			return nil, err
+		}
+		if err := p.consume(Identifier, "expect superclass method name"); err != nil {
+			return nil, err
+		}
+		expr = &SuperExpr{Keyword: keyword, Method: p.previous()}
+	case p.match(This):
+		expr = &ThisExpr{Keyword: p.previous()}
+	case p.match(Identifier):
+		expr = &Variable{Name: p.previous()}
 	case p.match(LeftParen):
 		innerExpr, err := p.Expression()
 		if err != nil {
 			return nil, err
 		}
-		err = p.consume(RightParen, "expect ')' after expression")
-		if err != nil {
-			p.error(p.previous(), err.Error())
+		if err := p.consume(RightParen, "expect ')' after expression"); err != nil {
 			return nil, err
 		}
 		expr = Grouping{Expr: innerExpr}
 	default:
 		token := p.peek()
 		errStr := fmt.Sprintf("unexpected token '%s'", token.Lexeme)
-		p.error(token, errStr)
-		return nil, fmt.Errorf("%s", errStr)
+		return nil, p.error(token, errStr)
 	}
 
 	return expr, nil
 }
 
-//nolint:unused
+// nest enters another level of nesting, returning a function that leaves
+// it, or reports a syntax error if the input nests too deeply.
+func (p *Parser) nest() (func(), error) {
+	if p.depth >= maxNestingDepth {
+		p.tooDeep = true
+		return nil, p.error(p.peek(), "too deeply nested")
+	}
+	p.depth++
+	return func() { p.depth-- }, nil
+}
+
 func (p *Parser) synchronize() {
 	p.advance()
 
@@ -154,12 +666,13 @@ func (p *Parser) synchronize() {
 	}
 }
 
-func (p *Parser) error(token Token, message string) {
-	if token.TokenType == EOF {
-		p.lox.report(token.Line, " at end", message)
-	} else {
-		p.lox.report(token.Line, " at '"+token.Lexeme+"'", message)
-	}
+// error reports a syntax error and records it for Parse to return. Callers
+// that cannot continue parsing return the result as their error.
+func (p *Parser) error(token Token, message string) ParseError {
+	p.lox.error(PhaseParse, CodeSyntax, p.lox.tokenSpan(token), message)
+	err := ParseError{Token: token, Message: message}
+	p.errors = append(p.errors, err)
+	return err
 }
 
 func (p *Parser) consume(tokenType TokenType, message string) error {
@@ -167,7 +680,7 @@ func (p *Parser) consume(tokenType TokenType, message string) error {
 		p.advance()
 		return nil
 	}
-	return fmt.Errorf("%s", message)
+	return p.error(p.peek(), message)
 }
 
 func (p *Parser) match(tokenTypes ...TokenType) bool {
@@ -1,9 +1,11 @@
 package lox
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParser_Expression(t *testing.T) {
@@ -408,7 +410,7 @@ func TestParser_Expression(t *testing.T) {
 			lox := &Lox{}
 			parser := NewParser(tt.tokens, lox)
 
-			expr, err := parser.Parse()
+			expr, err := parser.Expression()
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -550,7 +552,7 @@ func TestParser_Expression_Errors(t *testing.T) {
 
 			var expr Expr
 			output, err := captureOutput(func() error {
-				expr, _ = parser.Parse()
+				expr, _ = parser.Expression()
 				return nil
 			})
 
@@ -577,20 +579,60 @@ func TestParser_Expression_Errors(t *testing.T) {
 	}
 }
 
-func TestParser_Expression_EOFOnly(t *testing.T) {
+func TestParser_Parse_EOFOnly(t *testing.T) {
+	lox := &Lox{}
+	parser := NewParser([]Token{NewToken(EOF, "", nil, 1)}, lox)
+
+	statements, err := parser.Parse()
+
+	assert.NoError(t, err)
+	assert.Empty(t, statements, "expected no statements for EOF-only input")
+	assert.False(t, lox.hadError, "EOF-only input should not be an error")
+}
+
+func TestParser_Statements(t *testing.T) {
 	tests := []struct {
-		name        string
-		tokens      []Token
-		expectNil   bool
-		expectError bool
+		name     string
+		tokens   []Token
+		expected []Stmt
 	}{
 		{
-			name: "only EOF token",
+			name: "expression statement",
 			tokens: []Token{
+				NewToken(Number, "1", 1.0, 1),
+				NewToken(Semicolon, ";", nil, 1),
 				NewToken(EOF, "", nil, 1),
 			},
-			expectNil:   true,
-			expectError: false,
+			expected: []Stmt{
+				ExpressionStmt{Expr: Literal{Value: NewToken(Number, "1", 1.0, 1)}},
+			},
+		},
+		{
+			name: "print statement",
+			tokens: []Token{
+				NewToken(Print, "print", nil, 1),
+				NewToken(String, "\"hi\"", "hi", 1),
+				NewToken(Semicolon, ";", nil, 1),
+				NewToken(EOF, "", nil, 1),
+			},
+			expected: []Stmt{
+				PrintStmt{Expr: Literal{Value: NewToken(String, "\"hi\"", "hi", 1)}},
+			},
+		},
+		{
+			name: "multiple statements",
+			tokens: []Token{
+				NewToken(Print, "print", nil, 1),
+				NewToken(Number, "1", 1.0, 1),
+				NewToken(Semicolon, ";", nil, 1),
+				NewToken(True, "true", nil, 2),
+				NewToken(Semicolon, ";", nil, 2),
+				NewToken(EOF, "", nil, 2),
+			},
+			expected: []Stmt{
+				PrintStmt{Expr: Literal{Value: NewToken(Number, "1", 1.0, 1)}},
+				ExpressionStmt{Expr: Literal{Value: NewToken(True, "true", nil, 2)}},
+			},
 		},
 	}
 
@@ -599,20 +641,485 @@ func TestParser_Expression_EOFOnly(t *testing.T) {
 			lox := &Lox{}
 			parser := NewParser(tt.tokens, lox)
 
-			expr, err := parser.Parse()
+			statements, err := parser.Parse()
 
-			if tt.expectError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, statements)
+			assert.False(t, lox.hadError)
+		})
+	}
+}
 
-			if tt.expectNil {
-				assert.Nil(t, expr, "expected nil expression for EOF-only input")
-			}
+func TestParser_Declarations(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected []Stmt
+	}{
+		{
+			name:   "var without initializer",
+			source: "var a;",
+			expected: []Stmt{
+				VarStmt{Name: NewToken(Identifier, "a", nil, 1)},
+			},
+		},
+		{
+			name:   "var with initializer",
+			source: "var a = 1;",
+			expected: []Stmt{
+				VarStmt{
+					Name:        NewToken(Identifier, "a", nil, 1),
+					Initializer: Literal{Value: NewToken(Number, "1", 1.0, 1)},
+				},
+			},
+		},
+		{
+			name:   "assignment",
+			source: "a = b;",
+			expected: []Stmt{
+				ExpressionStmt{Expr: &Assign{
+					Name:  NewToken(Identifier, "a", nil, 1),
+					Value: &Variable{Name: NewToken(Identifier, "b", nil, 1)},
+				}},
+			},
+		},
+		{
+			name:   "block",
+			source: "{ var a; print a; }",
+			expected: []Stmt{
+				BlockStmt{Statements: []Stmt{
+					VarStmt{Name: NewToken(Identifier, "a", nil, 1)},
+					PrintStmt{Expr: &Variable{Name: NewToken(Identifier, "a", nil, 1)}},
+				}},
+			},
+		},
+		{
+			name:   "if else",
+			source: "if (a) print 1; else print 2;",
+			expected: []Stmt{
+				IfStmt{
+					Condition:  &Variable{Name: NewToken(Identifier, "a", nil, 1)},
+					ThenBranch: PrintStmt{Expr: Literal{Value: NewToken(Number, "1", 1.0, 1)}},
+					ElseBranch: PrintStmt{Expr: Literal{Value: NewToken(Number, "2", 2.0, 1)}},
+				},
+			},
+		},
+		{
+			name:   "while",
+			source: "while (a) a;",
+			expected: []Stmt{
+				WhileStmt{
+					Keyword:   NewToken(While, "while", nil, 1),
+					Condition: &Variable{Name: NewToken(Identifier, "a", nil, 1)},
+					Body:      ExpressionStmt{Expr: &Variable{Name: NewToken(Identifier, "a", nil, 1)}},
+				},
+			},
+		},
+		{
+			name:   "for desugars to while",
+			source: "for (var i; i; i) print i;",
+			expected: []Stmt{
+				BlockStmt{Statements: []Stmt{
+					VarStmt{Name: NewToken(Identifier, "i", nil, 1)},
+					WhileStmt{
+						Keyword:   NewToken(For, "for", nil, 1),
+						Condition: &Variable{Name: NewToken(Identifier, "i", nil, 1)},
+						Body: BlockStmt{Statements: []Stmt{
+							PrintStmt{Expr: &Variable{Name: NewToken(Identifier, "i", nil, 1)}},
+							ExpressionStmt{Expr: &Variable{Name: NewToken(Identifier, "i", nil, 1)}},
+						}},
+					},
+				}},
+			},
+		},
+		{
+			name:   "for without clauses loops on true",
+			source: "for (;;) {}",
+			expected: []Stmt{
+				WhileStmt{
+					Keyword:   NewToken(For, "for", nil, 1),
+					Condition: Literal{Value: NewToken(True, "true", nil, 1)},
+					Body:      BlockStmt{Statements: []Stmt{}},
+				},
+			},
+		},
+		{
+			name:   "logical operators",
+			source: "a or b and c;",
+			expected: []Stmt{
+				ExpressionStmt{Expr: Logical{
+					Left:     &Variable{Name: NewToken(Identifier, "a", nil, 1)},
+					Operator: NewToken(Or, "or", nil, 1),
+					Right: Logical{
+						Left:     &Variable{Name: NewToken(Identifier, "b", nil, 1)},
+						Operator: NewToken(And, "and", nil, 1),
+						Right:    &Variable{Name: NewToken(Identifier, "c", nil, 1)},
+					},
+				}},
+			},
+		},
+		{
+			name:   "function declaration",
+			source: "fun f(a, b) { return a; }",
+			expected: []Stmt{
+				FunctionStmt{
+					Name: NewToken(Identifier, "f", nil, 1),
+					Params: []Token{
+						NewToken(Identifier, "a", nil, 1),
+						NewToken(Identifier, "b", nil, 1),
+					},
+					Body: []Stmt{
+						ReturnStmt{
+							Keyword: NewToken(Return, "return", nil, 1),
+							Value:   &Variable{Name: NewToken(Identifier, "a", nil, 1)},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "bare return",
+			source: "return;",
+			expected: []Stmt{
+				ReturnStmt{Keyword: NewToken(Return, "return", nil, 1)},
+			},
+		},
+		{
+			name:   "chained calls",
+			source: "f(1)();",
+			expected: []Stmt{
+				ExpressionStmt{Expr: Call{
+					Callee: Call{
+						Callee:    &Variable{Name: NewToken(Identifier, "f", nil, 1)},
+						Paren:     NewToken(RightParen, ")", nil, 1),
+						Arguments: []Expr{Literal{Value: NewToken(Number, "1", 1.0, 1)}},
+					},
+					Paren:     NewToken(RightParen, ")", nil, 1),
+					Arguments: []Expr{},
+				}},
+			},
+		},
+		{
+			name:   "class declaration",
+			source: "class A { m() {} }",
+			expected: []Stmt{
+				ClassStmt{
+					Name: NewToken(Identifier, "A", nil, 1),
+					Methods: []FunctionStmt{
+						{
+							Name:   NewToken(Identifier, "m", nil, 1),
+							Params: []Token{},
+							Body:   []Stmt{},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "subclass declaration with super call",
+			source: "class B < A { m() { super.m(); } }",
+			expected: []Stmt{
+				ClassStmt{
+					Name:       NewToken(Identifier, "B", nil, 1),
+					Superclass: &Variable{Name: NewToken(Identifier, "A", nil, 1)},
+					Methods: []FunctionStmt{
+						{
+							Name:   NewToken(Identifier, "m", nil, 1),
+							Params: []Token{},
+							Body: []Stmt{
+								ExpressionStmt{Expr: Call{
+									Callee: &SuperExpr{
+										Keyword: NewToken(Super, "super", nil, 1),
+										Method:  NewToken(Identifier, "m", nil, 1),
+									},
+									Paren:     NewToken(RightParen, ")", nil, 1),
+									Arguments: []Expr{},
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "property get and set",
+			source: "this.a = b.c;",
+			expected: []Stmt{
+				ExpressionStmt{Expr: Set{
+					Object: &ThisExpr{Keyword: NewToken(This, "this", nil, 1)},
+					Name:   NewToken(Identifier, "a", nil, 1),
+					Value: Get{
+						Object: &Variable{Name: NewToken(Identifier, "b", nil, 1)},
+						Name:   NewToken(Identifier, "c", nil, 1),
+					},
+				}},
+			},
+		},
+		{
+			name:   "empty block",
+			source: "{}",
+			expected: []Stmt{
+				BlockStmt{Statements: []Stmt{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lox := &Lox{}
+			scanner := NewScanner(tt.source, lox)
+			parser := NewParser(withoutPositions(scanner.ScanTokens()), lox)
 
-			// Verify that hadError is false for EOF-only case
-			assert.False(t, lox.hadError, "EOF-only input should not be an error")
+			statements, err := parser.Parse()
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, statements)
+			assert.False(t, lox.hadError)
+		})
+	}
+}
+
+// withoutPositions clears column, offset and source information so scanned
+// tokens compare equal to ones built with NewToken.
+func withoutPositions(tokens []Token) []Token {
+	stripped := make([]Token, len(tokens))
+	for idx, token := range tokens {
+		stripped[idx] = NewToken(token.TokenType, token.Lexeme, token.Literal, token.Line)
+	}
+	return stripped
+}
+
+func TestParser_Declarations_Errors(t *testing.T) {
+	tests := []struct {
+		name             string
+		source           string
+		expectedErrorMsg string
+	}{
+		{
+			name:             "missing variable name",
+			source:           "var = 1;",
+			expectedErrorMsg: "expect variable name",
+		},
+		{
+			name:             "missing semicolon after declaration",
+			source:           "var a = 1",
+			expectedErrorMsg: "expect ';' after variable declaration",
+		},
+		{
+			name:             "unclosed block",
+			source:           "{ print 1;",
+			expectedErrorMsg: "expect '}' after block",
+		},
+		{
+			name:             "if without parenthesis",
+			source:           "if true print 1;",
+			expectedErrorMsg: "expect '(' after 'if'",
+		},
+		{
+			name:             "while without closing parenthesis",
+			source:           "while (true print 1;",
+			expectedErrorMsg: "expect ')' after condition",
+		},
+		{
+			name:             "for missing condition semicolon",
+			source:           "for (var i = 0; i < 1) print i;",
+			expectedErrorMsg: "expect ';' after loop condition",
+		},
+		{
+			name:             "function without name",
+			source:           "fun (a) {}",
+			expectedErrorMsg: "expect function name",
+		},
+		{
+			name:             "function without body",
+			source:           "fun f(a);",
+			expectedErrorMsg: "expect '{' before function body",
+		},
+		{
+			name:             "unclosed argument list",
+			source:           "f(1, 2;",
+			expectedErrorMsg: "expect ')' after arguments",
+		},
+		{
+			name:             "too many arguments",
+			source:           "f(" + strings.Repeat("1, ", 255) + "1);",
+			expectedErrorMsg: "can't have more than 255 arguments",
+		},
+		{
+			name:             "too many parameters",
+			source:           "fun f(" + strings.Repeat("a, ", 255) + "a) {}",
+			expectedErrorMsg: "can't have more than 255 parameters",
+		},
+		{
+			name:             "class without body",
+			source:           "class A;",
+			expectedErrorMsg: "expect '{' before class body",
+		},
+		{
+			name:             "missing superclass name",
+			source:           "class A < {}",
+			expectedErrorMsg: "expect superclass name",
+		},
+		{
+			name:             "super without method",
+			source:           "super;",
+			expectedErrorMsg: "expect '.' after 'super'",
+		},
+		{
+			name:             "missing property name",
+			source:           "a.;",
+			expectedErrorMsg: "expect property name after '.'",
+		},
+		{
+			name:             "invalid assignment target",
+			source:           "1 = 2;",
+			expectedErrorMsg: "invalid assignment target",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lox := &Lox{}
+			scanner := NewScanner(tt.source, lox)
+			parser := NewParser(scanner.ScanTokens(), lox)
+
+			output, _ := captureOutput(func() error {
+				_, err := parser.Parse()
+				return err
+			})
+
+			assert.True(t, lox.hadError)
+			assert.Contains(t, output, tt.expectedErrorMsg)
+		})
+	}
+}
+
+func TestParser_Statements_MissingSemicolon(t *testing.T) {
+	lox := &Lox{}
+	parser := NewParser([]Token{
+		NewToken(Print, "print", nil, 1),
+		NewToken(Number, "1", 1.0, 1),
+		NewToken(EOF, "", nil, 1),
+	}, lox)
+
+	output, err := captureOutput(func() error {
+		_, err := parser.Parse()
+		return err
+	})
+
+	assert.Error(t, err)
+	assert.True(t, lox.hadError)
+	assert.Contains(t, output, "expect ';' after value")
+}
+
+func TestParser_ReportsEverySyntaxError(t *testing.T) {
+	source := `var = 1;
+print 1
+var ok = 2;
+fun f( {}
+if (true) { print ; }
+class { }
+print ok;`
+
+	lox := &Lox{}
+	scanner := NewScanner(source, lox)
+	parser := NewParser(scanner.ScanTokens(), lox)
+
+	var statements []Stmt
+	output, err := captureOutput(func() error {
+		var err error
+		statements, err = parser.Parse()
+		return err
+	})
+
+	assert.Nil(t, statements)
+	assert.True(t, lox.hadError)
+
+	var parseErrors ParseErrors
+	require.ErrorAs(t, err, &parseErrors)
+	require.Len(t, parseErrors, 5)
+
+	assert.Equal(t, []string{
+		"expect variable name",
+		"expect ';' after value",
+		"expect parameter name",
+		"unexpected token ';'",
+		"expect class name",
+	}, []string{
+		parseErrors[0].Message,
+		parseErrors[1].Message,
+		parseErrors[2].Message,
+		parseErrors[3].Message,
+		parseErrors[4].Message,
+	})
+	assert.Equal(t, []int{1, 3, 4, 5, 6}, []int{
+		parseErrors[0].Token.Line,
+		parseErrors[1].Token.Line,
+		parseErrors[2].Token.Line,
+		parseErrors[3].Token.Line,
+		parseErrors[4].Token.Line,
+	})
+	assert.Equal(t, 5, strings.Count(output, "Error"))
+}
+
+func TestParser_NonFatalErrorsAreCollected(t *testing.T) {
+	lox := &Lox{}
+	scanner := NewScanner("1 = 2; print 3;", lox)
+	parser := NewParser(scanner.ScanTokens(), lox)
+
+	_, err := captureOutput(func() error {
+		_, err := parser.Parse()
+		return err
+	})
+
+	var parseErrors ParseErrors
+	require.ErrorAs(t, err, &parseErrors)
+	require.Len(t, parseErrors, 1)
+	assert.Equal(t, "invalid assignment target", parseErrors[0].Message)
+	assert.Equal(t, Equal, parseErrors[0].Token.TokenType)
+}
+
+func TestParser_LimitsNesting(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"unary operators", strings.Repeat("-", 3_000_000) + "1;"},
+		{"groupings", strings.Repeat("(", 100_000) + "1" + strings.Repeat(")", 100_000) + ";"},
+		{"assignments", strings.Repeat("a = ", 100_000) + "1;"},
+		{"blocks", strings.Repeat("{", 100_000) + strings.Repeat("}", 100_000)},
+		{"if statements", strings.Repeat("if (true) ", 100_000) + "print 1;"},
+		{"functions", strings.Repeat("fun f() { ", 300_000) + strings.Repeat("}", 300_000)},
+		{"methods", strings.Repeat("class A { m() { ", maxNestingDepth+1) + strings.Repeat("} }", maxNestingDepth+1)},
+		{"unclosed blocks", strings.Repeat("{ print 1; ", 100_000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lox := &Lox{}
+			lox.SetDiagnosticSink(&DiagnosticCollector{})
+			scanner := NewScanner(tt.source, lox)
+			parser := NewParser(scanner.ScanTokens(), lox)
+
+			_, err := parser.Parse()
+
+			var parseErrors ParseErrors
+			require.ErrorAs(t, err, &parseErrors)
+			require.Len(t, parseErrors, 1)
+			assert.Equal(t, "too deeply nested", parseErrors[0].Message)
 		})
 	}
 }
+
+func TestParser_AllowsNestingWithinTheLimit(t *testing.T) {
+	source := strings.Repeat("{ ", 100) + "print " + strings.Repeat("-(", 100) + "1" +
+		strings.Repeat(")", 100) + ";" + strings.Repeat(" }", 100)
+
+	lox := &Lox{}
+	scanner := NewScanner(source, lox)
+	parser := NewParser(scanner.ScanTokens(), lox)
+
+	statements, err := parser.Parse()
+	require.NoError(t, err)
+	assert.Len(t, statements, 1)
+}
@@ -0,0 +1,183 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// prelude creates the natives every interpreter starts with. None of them
+// touch the host, so unlike builtins they need no capability. Strings and
+// lists they create count against the allocation limit.
+func prelude() []*NativeFunction {
+	return []*NativeFunction{
+		newPreludeFunction("len", 1, func(_ *Interpreter, arguments []any) (any, error) {
+			switch value := arguments[0].(type) {
+			case string:
+				return float64(utf8.RuneCountInString(value)), nil
+			case *LoxList:
+				return float64(len(value.elements)), nil
+			default:
+				return nil, InvalidArgument("len", 0, "a string or a list", value)
+			}
+		}),
+		newPreludeFunction("str", 1, func(interpreter *Interpreter, arguments []any) (any, error) {
+			if s, ok := arguments[0].(string); ok {
+				return s, nil
+			}
+			s := stringify(arguments[0])
+			if err := interpreter.allocateString(len(s)); err != nil {
+				return nil, err
+			}
+			return s, nil
+		}),
+		newPreludeFunction("num", 1, func(_ *Interpreter, arguments []any) (any, error) {
+			switch value := arguments[0].(type) {
+			case float64:
+				return value, nil
+			case string:
+				return parseNumber(value), nil
+			default:
+				return nil, InvalidArgument("num", 0, "a string or a number", value)
+			}
+		}),
+		newPreludeFunction("type", 1, func(_ *Interpreter, arguments []any) (any, error) {
+			return TypeName(arguments[0]), nil
+		}),
+		mathFunction("floor", math.Floor),
+		mathFunction("sqrt", math.Sqrt),
+		mathFunction("abs", math.Abs),
+		newPreludeFunction("min", 2, func(_ *Interpreter, arguments []any) (any, error) {
+			a, b, err := numberArguments("min", arguments)
+			if err != nil {
+				return nil, err
+			}
+			return math.Min(a, b), nil
+		}),
+		newPreludeFunction("max", 2, func(_ *Interpreter, arguments []any) (any, error) {
+			a, b, err := numberArguments("max", arguments)
+			if err != nil {
+				return nil, err
+			}
+			return math.Max(a, b), nil
+		}),
+		newPreludeFunction("substr", 3, substr),
+		stringFunction("upper", strings.ToUpper),
+		stringFunction("lower", strings.ToLower),
+		newPreludeFunction("split", 2, split),
+	}
+}
+
+func newPreludeFunction(name string, arity int, fn func(*Interpreter, []any) (any, error)) *NativeFunction {
+	return &NativeFunction{Name: name, arity: arity, fn: fn}
+}
+
+// mathFunction adapts a function of one number.
+func mathFunction(name string, fn func(float64) float64) *NativeFunction {
+	return newPreludeFunction(name, 1, func(_ *Interpreter, arguments []any) (any, error) {
+		n, err := numberArgument(name, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		return fn(n), nil
+	})
+}
+
+// stringFunction adapts a function transforming one string.
+func stringFunction(name string, fn func(string) string) *NativeFunction {
+	return newPreludeFunction(name, 1, func(interpreter *Interpreter, arguments []any) (any, error) {
+		s, err := stringArgument(name, arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		// Case mapping can change a string's length, so charge the result.
+		result := fn(s)
+		if err := interpreter.allocateString(len(result)); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+}
+
+func numberArguments(native string, arguments []any) (float64, float64, error) {
+	a, err := numberArgument(native, arguments, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	b, err := numberArgument(native, arguments, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return a, b, nil
+}
+
+// parseNumber reads s as a decimal number, ignoring surrounding whitespace.
+// Anything else, including the infinities and NaN that Go would accept,
+// gives nil.
+func parseNumber(s string) any {
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return nil
+	}
+	return n
+}
+
+// substr returns the length characters of a string starting at start.
+func substr(interpreter *Interpreter, arguments []any) (any, error) {
+	s, err := stringArgument("substr", arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	start, err := integerArgument("substr", arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	length, err := integerArgument("substr", arguments, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(s)
+	if start < 0 || length < 0 || start+length > len(runes) {
+		return nil, &RuntimeError{
+			Err: fmt.Errorf(
+				"substring of length %d at %d is out of range for a string of length %d",
+				length, start, len(runes),
+			),
+			Code: CodeIndexOutOfRange,
+		}
+	}
+	substring := string(runes[start : start+length])
+	if err := interpreter.allocateString(len(substring)); err != nil {
+		return nil, err
+	}
+	return substring, nil
+}
+
+// split breaks a string around each occurrence of a separator, or into
+// characters if the separator is empty.
+func split(interpreter *Interpreter, arguments []any) (any, error) {
+	s, err := stringArgument("split", arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	separator, err := stringArgument("split", arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(s, separator)
+	elements := make([]any, len(parts))
+	for idx, part := range parts {
+		if err := interpreter.allocateString(len(part)); err != nil {
+			return nil, err
+		}
+		elements[idx] = part
+	}
+	if err := interpreter.allocate(); err != nil {
+		return nil, err
+	}
+	return NewLoxList(elements), nil
+}
@@ -0,0 +1,161 @@
+package lox
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrelude(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{"len of a string", `len("héllo");`, 5.0},
+		{"len of an empty string", `len("");`, 0.0},
+		{"len of a list", `len(split("a,b,c", ","));`, 3.0},
+		{"str of a number", `str(42);`, "42"},
+		{"str of a fraction", `str(2.5);`, "2.5"},
+		{"str of a string", `str("s");`, "s"},
+		{"str of a boolean", `str(true);`, "true"},
+		{"str concatenates", `"n = " + str(1 + 2);`, "n = 3"},
+		{"num parses", `num("3.25");`, 3.25},
+		{"num trims whitespace", `num(" 7 ");`, 7.0},
+		{"num of a number", `num(7);`, 7.0},
+		{"num of garbage", `num("seven");`, nil},
+		{"num of infinity", `num("Inf");`, nil},
+		{"type of nil", `type(nil);`, "nil"},
+		{"type of a number", `type(1);`, "number"},
+		{"type of a string", `type("s");`, "string"},
+		{"type of a function", `type(len);`, "function"},
+		{"type of a class", `class A {} type(A);`, "class"},
+		{"type of an instance", `class A {} type(A());`, "instance"},
+		{"type of a list", `type(split("", ","));`, "list"},
+		{"floor", `floor(-2.5);`, -3.0},
+		{"sqrt", `sqrt(16);`, 4.0},
+		{"abs", `abs(-3);`, 3.0},
+		{"min", `min(2, -1);`, -1.0},
+		{"max", `max(2, -1);`, 2.0},
+		{"substr", `substr("héllo", 1, 3);`, "éll"},
+		{"substr to the end", `substr("abc", 3, 0);`, ""},
+		{"upper", `upper("abc");`, "ABC"},
+		{"lower", `lower("ÀBC");`, "àbc"},
+		{"split", `split("a,b,,c", ",").get(3);`, "c"},
+		{"split into characters", `split("héy", "").get(1);`, "é"},
+		{"split length", `split("a b", " ").length;`, 2.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Lox{}
+			l.SetOutput(io.Discard, io.Discard)
+
+			value, err := l.Eval(context.Background(), tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestPrelude_Errors(t *testing.T) {
+	tests := []struct {
+		name            string
+		source          string
+		expectedCode    string
+		expectedMessage string
+	}{
+		{
+			name:            "len of a number",
+			source:          "len(1);",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 1 to len must be a string or a list, got number",
+		},
+		{
+			name:            "num of nil",
+			source:          "num(nil);",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 1 to num must be a string or a number, got nil",
+		},
+		{
+			name:            "sqrt of a string",
+			source:          `sqrt("4");`,
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 1 to sqrt must be a number, got string",
+		},
+		{
+			name:            "max of a boolean",
+			source:          "max(1, true);",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 2 to max must be a number, got boolean",
+		},
+		{
+			name:            "upper of a number",
+			source:          "upper(1);",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 1 to upper must be a string, got number",
+		},
+		{
+			name:            "substr with a fractional start",
+			source:          `substr("abc", 0.5, 1);`,
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 2 to substr must be an integer, got number",
+		},
+		{
+			name:            "substr past the end",
+			source:          `substr("abc", 1, 3);`,
+			expectedCode:    CodeIndexOutOfRange,
+			expectedMessage: "substring of length 3 at 1 is out of range for a string of length 3",
+		},
+		{
+			name:            "substr before the start",
+			source:          `substr("abc", -1, 1);`,
+			expectedCode:    CodeIndexOutOfRange,
+			expectedMessage: "substring of length 1 at -1 is out of range for a string of length 3",
+		},
+		{
+			name:            "split with a nil separator",
+			source:          `split("abc", nil);`,
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 2 to split must be a string, got nil",
+		},
+		{
+			name:            "wrong arity",
+			source:          "min(1);",
+			expectedCode:    CodeArityMismatch,
+			expectedMessage: "expected 2 arguments but got 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Lox{}
+			l.SetOutput(io.Discard, io.Discard)
+
+			_, err := l.Eval(context.Background(), tt.source)
+
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, tt.expectedCode, runtimeErr.Code)
+			assert.Equal(t, tt.expectedMessage, runtimeErr.Error())
+			assert.Equal(t, 1, runtimeErr.Token.Line)
+		})
+	}
+}
+
+func TestPrelude_CountsAllocations(t *testing.T) {
+	l := &Lox{}
+	l.SetOutput(io.Discard, io.Discard)
+	l.SetLimits(Limits{MaxAllocations: 3})
+
+	_, err := l.Eval(context.Background(), `split("a,b", ",");`)
+	require.NoError(t, err)
+
+	_, err = l.Eval(context.Background(), `split("a,b,c", ",");`)
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeAllocationLimit, runtimeErr.Code)
+}
@@ -0,0 +1,204 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	prompt             = "> "
+	continuationPrompt = "... "
+)
+
+// runPrompt starts the REPL, editing lines in place when stdin is a
+// terminal.
+func (l *Lox) runPrompt() int {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return l.repl(newPlainLineReader(os.Stdin, l.output()))
+	}
+
+	editor := newLineEditor(os.Stdin, os.Stdout, loadHistory(historyPath()), l.complete)
+	editor.makeRaw = func() (func(), error) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, err
+		}
+		return func() { _ = term.Restore(fd, state) }, nil
+	}
+	return l.repl(editor)
+}
+
+// repl reads entries from lines and runs each in one persistent session,
+// so declarations carry over between them, echoing the value of an entry
+// that ends in a bare expression. An entry continues onto further
+// lines while it is incomplete; Ctrl-C abandons it.
+func (l *Lox) repl(lines lineReader) int {
+	var entry strings.Builder
+	for {
+		currentPrompt := prompt
+		if entry.Len() > 0 {
+			currentPrompt = continuationPrompt
+		}
+
+		line, err := lines.ReadLine(currentPrompt)
+		if errors.Is(err, errInterrupted) {
+			entry.Reset()
+			continue
+		}
+		if entry.Len() == 0 && strings.HasPrefix(strings.TrimSpace(line), ":") {
+			l.command(strings.TrimSpace(line))
+			if err != nil {
+				_, _ = fmt.Fprintln(l.output())
+				break
+			}
+			continue
+		}
+		entry.WriteString(line)
+		if err != nil {
+			_, _ = fmt.Fprintln(l.output())
+			// Run whatever was left unfinished so its errors are reported.
+			l.runEntry(entry.String())
+			break
+		}
+		entry.WriteString("\n")
+		if incomplete(entry.String()) {
+			continue
+		}
+		l.runEntry(entry.String())
+		entry.Reset()
+	}
+	return 0
+}
+
+func (l *Lox) runEntry(source string) {
+	if strings.TrimSpace(source) == "" {
+		return
+	}
+	// The final newline only ended the entry; keep it out of snippets.
+	source = strings.TrimRight(source, "\r\n")
+	ctx, cancel := l.runContext()
+	defer cancel()
+	// Every error has already been reported by the time it is returned.
+	value, expression, err := l.eval(ctx, source)
+	if expression && err == nil {
+		_, _ = fmt.Fprintln(l.output(), stringify(value))
+	}
+}
+
+// replHelp describes the meta-commands the REPL accepts alongside Lox.
+const replHelp = `:ast <source>     print the syntax tree of source
+:tokens <source>  print the tokens scanned from source
+:env              list the session's global variables
+:load <path>      run a file in the session
+:reset            discard every declaration and start a fresh session
+:time <source>    run source and report how long it took
+:help             show this help
+`
+
+// command runs a REPL meta-command: a line starting with ':'.
+func (l *Lox) command(line string) {
+	name, argument, _ := strings.Cut(line, " ")
+	argument = strings.TrimSpace(argument)
+
+	switch name {
+	case ":ast":
+		statements, ok := l.parse(argument)
+		if !ok {
+			return
+		}
+		printer := NewAstPrinter()
+		for _, statement := range statements {
+			_, _ = fmt.Fprintln(l.output(), printer.PrintStatement(statement))
+		}
+	case ":tokens":
+		l.source, l.errors, l.hadError = argument, nil, false
+		scanner := NewScanner(argument, l)
+		for _, token := range scanner.ScanTokens() {
+			_, _ = fmt.Fprintf(l.output(), "%d:%d %s %q\n", token.Line, token.Column, token.TokenType, token.Lexeme)
+		}
+	case ":env":
+		l.printEnvironment()
+	case ":load":
+		if argument == "" {
+			_, _ = fmt.Fprintln(l.errorOutput(), "usage: :load <path>")
+			return
+		}
+		ctx, cancel := l.runContext()
+		defer cancel()
+		var pathErr *os.PathError
+		if _, err := l.EvalFile(ctx, argument); errors.As(err, &pathErr) {
+			_, _ = fmt.Fprintf(l.errorOutput(), "can't load %s: %v\n", argument, pathErr.Err)
+		}
+	case ":reset":
+		l.interpreter = nil
+	case ":time":
+		start := time.Now()
+		l.runEntry(argument)
+		_, _ = fmt.Fprintf(l.output(), "took %s\n", time.Since(start))
+	case ":help":
+		_, _ = fmt.Fprint(l.output(), replHelp)
+	default:
+		_, _ = fmt.Fprintf(l.errorOutput(), "unknown command '%s' (try :help)\n", name)
+	}
+}
+
+// parse scans and parses source without running it, reporting any errors.
+func (l *Lox) parse(source string) ([]Stmt, bool) {
+	l.source, l.errors, l.hadError = source, nil, false
+	scanner := NewScanner(source, l)
+	parser := NewParser(scanner.ScanTokens(), l)
+	statements, _ := parser.Parse()
+	return statements, !l.hadError
+}
+
+// printEnvironment lists the session's globals in name order. Natives are
+// left out, since every session starts with the same ones.
+func (l *Lox) printEnvironment() {
+	values := l.session().globals.values
+	names := make([]string, 0, len(values))
+	for name, value := range values {
+		if _, native := value.(*NativeFunction); !native {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(l.output(), "%s = %s\n", name, stringify(values[name]))
+	}
+}
+
+// incomplete reports whether source stops part way through something more
+// lines could finish: an unclosed '(' or '{', or an unterminated string.
+// Anything else, including a stray closing bracket, is left for the parser
+// to report.
+func incomplete(source string) bool {
+	collector := &DiagnosticCollector{}
+	scanLox := &Lox{}
+	scanLox.SetDiagnosticSink(collector)
+	scanner := NewScanner(source, scanLox)
+	tokens := scanner.ScanTokens()
+
+	for _, d := range collector.Diagnostics {
+		if d.Code == CodeUnterminatedString {
+			return true
+		}
+	}
+
+	depth := 0
+	for _, token := range tokens {
+		switch token.TokenType {
+		case LeftParen, LeftBrace:
+			depth++
+		case RightParen, RightBrace:
+			depth--
+		}
+	}
+	return depth > 0
+}
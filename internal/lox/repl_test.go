@@ -0,0 +1,209 @@
+package lox
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncomplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected bool
+	}{
+		{"empty", "", false},
+		{"complete statement", "print 1;\n", false},
+		{"missing semicolon", "print 1\n", false},
+		{"open brace", "fun f() {\n", true},
+		{"nested braces", "if (a) {\n  while (b) {\n  }\n", true},
+		{"closed braces", "{\n  print 1;\n}\n", false},
+		{"open paren", "print (1 +\n", true},
+		{"unterminated string", "print \"hello\n", true},
+		{"brace inside a string", "print \"{\";\n", false},
+		{"brace inside a comment", "// {\n", false},
+		{"stray closing brace", "}\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, incomplete(tt.source))
+		})
+	}
+}
+
+func TestLox_repl(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "state persists between entries",
+			input:    "var a = 1;\nprint a + 1;\n",
+			expected: "> > 2\n> \n",
+		},
+		{
+			name:     "functions persist between entries",
+			input:    "fun double(n) { return n * 2; }\nprint double(4);\n",
+			expected: "> > 8\n> \n",
+		},
+		{
+			name:     "blocks continue over lines",
+			input:    "fun greet(name) {\n  print \"hi \" + name;\n}\ngreet(\"lox\");\n",
+			expected: "> ... ... > hi lox\nnil\n> \n",
+		},
+		{
+			name:     "bare expressions are echoed",
+			input:    "1 + 2;\n\"a\" + \"b\";\n3.0;\ntrue;\n",
+			expected: "> 3\n> ab\n> 3\n> true\n> \n",
+		},
+		{
+			name:     "nil is echoed as nil",
+			input:    "nil;\nvar a;\na;\n",
+			expected: "> nil\n> > nil\n> \n",
+		},
+		{
+			name:     "only a final expression is echoed",
+			input:    "1; 2;\nprint 3; var b = 4;\nvar c = 5; c = c + 1;\n",
+			expected: "> 2\n> 3\n> 6\n> \n",
+		},
+		{
+			name:  "failed expressions aren't echoed",
+			input: "nil + 1;\n",
+			expected: "> operands to + must be two numbers or two strings, got nil and number\n[<stdin>:1:5] in script\n" +
+				"  |\n1 | nil + 1;\n  |     ^\n> \n",
+		},
+		{
+			name:     "strings continue over lines",
+			input:    "print \"a\nb\";\n",
+			expected: "> ... a\nb\n> \n",
+		},
+		{
+			name:     "blank lines are skipped",
+			input:    "\n\nprint 1;\n",
+			expected: "> > > 1\n> \n",
+		},
+		{
+			name:  "errors don't end the session",
+			input: "print missing;\nprint 2;\n",
+			expected: "> undefined variable 'missing'\n[<stdin>:1:7] in script\n" +
+				"  |\n1 | print missing;\n  |       ^~~~~~~\n> 2\n> \n",
+		},
+		{
+			name:  "unfinished entry is reported at end of input",
+			input: "{ print 1;\n",
+			expected: "> ... \n<stdin>:1:11: Error at end: expect '}' after block\n" +
+				"  |\n1 | { print 1;\n  |           ^\n",
+		},
+		{
+			name:     "ast command",
+			input:    ":ast var a = 1 + 2 * 3; print a;\n",
+			expected: "> (var a (+ 1 (* 2 3)))\n(print a)\n> \n",
+		},
+		{
+			name:  "ast command reports parse errors",
+			input: ":ast print (1;\n",
+			expected: "> <stdin>:1:9: Error at ';': expect ')' after expression\n" +
+				"  |\n1 | print (1;\n  |         ^\n> \n",
+		},
+		{
+			name:     "tokens command",
+			input:    ":tokens var a = \"hi\";\n",
+			expected: "> 1:1 Var \"var\"\n1:5 Identifier \"a\"\n1:7 Equal \"=\"\n1:9 String \"\\\"hi\\\"\"\n1:13 Semicolon \";\"\n1:14 EOF \"\"\n> \n",
+		},
+		{
+			name:     "env command lists globals",
+			input:    "var b = 2; var a = \"x\"; fun f() {}\n:env\n",
+			expected: "> > a = x\nb = 2\nf = <fn f>\n> \n",
+		},
+		{
+			name:     "reset command forgets declarations",
+			input:    "var a = 1;\n:reset\n:env\nprint len(\"ab\");\n",
+			expected: "> > > > 2\n> \n",
+		},
+		{
+			name:     "load command reports missing files",
+			input:    ":load /does/not/exist.lox\n",
+			expected: "> can't load /does/not/exist.lox: no such file or directory\n> \n",
+		},
+		{
+			name:     "unknown commands",
+			input:    ":nope\n",
+			expected: "> unknown command ':nope' (try :help)\n> \n",
+		},
+		{
+			name:     "help command",
+			input:    ":help\n",
+			expected: "> " + replHelp + "> \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := &Lox{}
+			l.SetOutput(&out, &out)
+
+			assert.Equal(t, 0, l.repl(newPlainLineReader(strings.NewReader(tt.input), &out)))
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
+
+func TestLox_repl_LoadAndTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lib.lox")
+	require.NoError(t, os.WriteFile(path, []byte("fun square(n) { return n * n; }\n"), 0o600))
+
+	var out bytes.Buffer
+	l := &Lox{}
+	l.SetOutput(&out, &out)
+	input := ":load " + path + "\n:time square(3);\n"
+
+	assert.Equal(t, 0, l.repl(newPlainLineReader(strings.NewReader(input), &out)))
+	assert.Regexp(t, `^> > 9\ntook \S+s\n> \n$`, out.String())
+}
+
+func TestLox_repl_ErrorsPointIntoTheirOwnSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lib.lox")
+	require.NoError(t, os.WriteFile(path, []byte("fun f() {\n  return nil + 1;\n}\n"), 0o600))
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "function from a loaded file",
+			input: ":load " + path + "\nf();\n",
+			expected: "> > operands to + must be two numbers or two strings, got nil and number\n" +
+				"[" + path + ":2:14] in f()\n" +
+				"[<stdin>:1:3] in script\n" +
+				"  |\n1 | fun f() {\n2 |   return nil + 1;\n  |              ^\n3 | }\n> \n",
+		},
+		{
+			name:  "function from an earlier entry",
+			input: "fun g() {\n  return -nil;\n}\n{\ng();\n}\n",
+			expected: "> ... ... > ... ... operand to - must be a number, got nil\n" +
+				"[<stdin>:2:10] in g()\n" +
+				"[<stdin>:2:3] in script\n" +
+				"  |\n1 | fun g() {\n2 |   return -nil;\n  |          ^\n3 | }\n> \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := &Lox{}
+			l.SetOutput(&out, &out)
+
+			assert.Equal(t, 0, l.repl(newPlainLineReader(strings.NewReader(tt.input), &out)))
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
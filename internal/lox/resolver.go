@@ -0,0 +1,256 @@
+package lox
+
+type functionType int
+
+const (
+	noFunction functionType = iota
+	inFunction
+	inInitializer
+	inMethod
+)
+
+type classType int
+
+const (
+	noClass classType = iota
+	inClass
+	inSubclass
+)
+
+// Resolver is a static pass run between parsing and interpretation. It
+// records how many scopes separate each local variable reference from its
+// declaration, and reports scoping mistakes before any code runs.
+type Resolver struct {
+	interpreter *Interpreter
+	lox         *Lox
+
+	// scopes is a stack of block scopes. Each maps a variable name to
+	// whether its initializer has finished resolving. The global scope is
+	// not tracked.
+	scopes          []map[string]bool
+	currentFunction functionType
+	currentClass    classType
+}
+
+// NewResolver creates a Resolver that records its results in interpreter.
+func NewResolver(interpreter *Interpreter, lox *Lox) Resolver {
+	return Resolver{
+		interpreter: interpreter,
+		lox:         lox,
+	}
+}
+
+// Resolve walks every statement, reporting any errors through Lox.
+func (r *Resolver) Resolve(statements []Stmt) {
+	for _, stmt := range statements {
+		stmt.Accept(r)
+	}
+}
+
+func (r *Resolver) VisitBlockStmt(s BlockStmt) {
+	r.beginScope()
+	r.Resolve(s.Statements)
+	r.endScope()
+}
+
+func (r *Resolver) VisitClassStmt(s ClassStmt) {
+	enclosingClass := r.currentClass
+	r.currentClass = inClass
+	defer func() { r.currentClass = enclosingClass }()
+
+	r.declare(s.Name)
+	r.define(s.Name)
+
+	if s.Superclass != nil {
+		if s.Superclass.Name.Lexeme == s.Name.Lexeme {
+			r.error(s.Superclass.Name, "a class can't inherit from itself")
+		}
+		r.currentClass = inSubclass
+		r.VisitVariable(s.Superclass)
+
+		r.beginScope()
+		r.scopes[len(r.scopes)-1]["super"] = true
+		defer r.endScope()
+	}
+
+	r.beginScope()
+	r.scopes[len(r.scopes)-1]["this"] = true
+	for _, method := range s.Methods {
+		kind := inMethod
+		if method.Name.Lexeme == "init" {
+			kind = inInitializer
+		}
+		r.resolveFunction(method, kind)
+	}
+	r.endScope()
+}
+
+func (r *Resolver) VisitExpressionStmt(s ExpressionStmt) {
+	s.Expr.Accept(r)
+}
+
+func (r *Resolver) VisitFunctionStmt(s FunctionStmt) {
+	r.declare(s.Name)
+	r.define(s.Name)
+	r.resolveFunction(s, inFunction)
+}
+
+func (r *Resolver) VisitIfStmt(s IfStmt) {
+	s.Condition.Accept(r)
+	s.ThenBranch.Accept(r)
+	if s.ElseBranch != nil {
+		s.ElseBranch.Accept(r)
+	}
+}
+
+func (r *Resolver) VisitPrintStmt(s PrintStmt) {
+	s.Expr.Accept(r)
+}
+
+func (r *Resolver) VisitReturnStmt(s ReturnStmt) {
+	if r.currentFunction == noFunction {
+		r.error(s.Keyword, "can't return from top-level code")
+	}
+	if s.Value != nil {
+		if r.currentFunction == inInitializer {
+			r.error(s.Keyword, "can't return a value from an initializer")
+		}
+		s.Value.Accept(r)
+	}
+}
+
+func (r *Resolver) VisitVarStmt(s VarStmt) {
+	r.declare(s.Name)
+	if s.Initializer != nil {
+		s.Initializer.Accept(r)
+	}
+	r.define(s.Name)
+}
+
+func (r *Resolver) VisitWhileStmt(s WhileStmt) {
+	s.Condition.Accept(r)
+	s.Body.Accept(r)
+}
+
+func (r *Resolver) VisitAssign(a *Assign) {
+	a.Value.Accept(r)
+	r.resolveLocal(a, a.Name)
+}
+
+func (r *Resolver) VisitBinary(b Binary) {
+	b.Left.Accept(r)
+	b.Right.Accept(r)
+}
+
+func (r *Resolver) VisitCall(c Call) {
+	c.Callee.Accept(r)
+	for _, argument := range c.Arguments {
+		argument.Accept(r)
+	}
+}
+
+func (r *Resolver) VisitGet(g Get) {
+	g.Object.Accept(r)
+}
+
+func (r *Resolver) VisitGrouping(g Grouping) {
+	g.Expr.Accept(r)
+}
+
+func (r *Resolver) VisitLiteral(_ Literal) {}
+
+func (r *Resolver) VisitLogical(l Logical) {
+	l.Left.Accept(r)
+	l.Right.Accept(r)
+}
+
+func (r *Resolver) VisitSet(s Set) {
+	s.Value.Accept(r)
+	s.Object.Accept(r)
+}
+
+func (r *Resolver) VisitSuper(s *SuperExpr) {
+	switch r.currentClass {
+	case noClass:
+		r.error(s.Keyword, "can't use 'super' outside of a class")
+	case inClass:
+		r.error(s.Keyword, "can't use 'super' in a class with no superclass")
+	}
+	r.resolveLocal(s, s.Keyword)
+}
+
+func (r *Resolver) VisitThis(t *ThisExpr) {
+	if r.currentClass == noClass {
+		r.error(t.Keyword, "can't use 'this' outside of a class")
+		return
+	}
+	r.resolveLocal(t, t.Keyword)
+}
+
+func (r *Resolver) VisitUnary(u Unary) {
+	u.Right.Accept(r)
+}
+
+func (r *Resolver) VisitVariable(v *Variable) {
+	if len(r.scopes) > 0 {
+		if defined, ok := r.scopes[len(r.scopes)-1][v.Name.Lexeme]; ok && !defined {
+			r.error(v.Name, "can't read local variable in its own initializer")
+		}
+	}
+	r.resolveLocal(v, v.Name)
+}
+
+func (r *Resolver) resolveFunction(function FunctionStmt, kind functionType) {
+	enclosingFunction := r.currentFunction
+	r.currentFunction = kind
+	defer func() { r.currentFunction = enclosingFunction }()
+
+	r.beginScope()
+	for _, param := range function.Params {
+		r.declare(param)
+		r.define(param)
+	}
+	r.Resolve(function.Body)
+	r.endScope()
+}
+
+// resolveLocal records the depth of the innermost scope declaring name.
+// Names not found in any scope are left unresolved and assumed global.
+func (r *Resolver) resolveLocal(expr Expr, name Token) {
+	for idx := len(r.scopes) - 1; idx >= 0; idx-- {
+		if _, ok := r.scopes[idx][name.Lexeme]; ok {
+			r.interpreter.resolve(expr, len(r.scopes)-1-idx)
+			return
+		}
+	}
+}
+
+func (r *Resolver) beginScope() {
+	r.scopes = append(r.scopes, map[string]bool{})
+}
+
+func (r *Resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *Resolver) declare(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+	scope := r.scopes[len(r.scopes)-1]
+	if _, ok := scope[name.Lexeme]; ok {
+		r.error(name, "already a variable with this name in this scope")
+	}
+	scope[name.Lexeme] = false
+}
+
+func (r *Resolver) define(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+	r.scopes[len(r.scopes)-1][name.Lexeme] = true
+}
+
+func (r *Resolver) error(token Token, message string) {
+	r.lox.error(PhaseResolve, CodeResolution, r.lox.tokenSpan(token), message)
+}
@@ -0,0 +1,131 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_ClosureBinding(t *testing.T) {
+	source := `var a = "global";
+{
+  fun showA() {
+    print a;
+  }
+
+  showA();
+  var a = "block";
+  showA();
+}`
+
+	lox := &Lox{}
+	output, err := interpretSource(lox, source)
+
+	require.NoError(t, err)
+	assert.False(t, lox.hadError)
+	assert.Equal(t, "global\nglobal\n", output)
+}
+
+func TestResolver_RecordsDepths(t *testing.T) {
+	lox := &Lox{}
+	scanner := NewScanner("{ var a; { a; } } a;", lox)
+	parser := NewParser(scanner.ScanTokens(), lox)
+	statements, err := parser.Parse()
+	require.NoError(t, err)
+
+	interpreter := NewInterpreter(lox)
+	resolver := NewResolver(&interpreter, lox)
+	resolver.Resolve(statements)
+
+	outer := statements[0].(BlockStmt)
+	inner := outer.Statements[1].(BlockStmt)
+	local := inner.Statements[0].(ExpressionStmt).Expr
+	global := statements[1].(ExpressionStmt).Expr
+
+	depth, ok := interpreter.locals[local]
+	assert.True(t, ok)
+	assert.Equal(t, 1, depth)
+	_, ok = interpreter.locals[global]
+	assert.False(t, ok, "globals should not be resolved")
+}
+
+func TestResolver_Errors(t *testing.T) {
+	tests := []struct {
+		name             string
+		source           string
+		expectedErrorMsg string
+	}{
+		{
+			name:             "read local in its own initializer",
+			source:           "var a = 1; { var a = a; }",
+			expectedErrorMsg: "<stdin>:1:22: Error at 'a': can't read local variable in its own initializer",
+		},
+		{
+			name:             "duplicate local declaration",
+			source:           "{\n  var a = 1;\n  var a = 2;\n}",
+			expectedErrorMsg: "<stdin>:3:7: Error at 'a': already a variable with this name in this scope",
+		},
+		{
+			name:             "duplicate parameter",
+			source:           "fun f(a, a) {}",
+			expectedErrorMsg: "already a variable with this name in this scope",
+		},
+		{
+			name:             "this outside of a class",
+			source:           "print this;",
+			expectedErrorMsg: "can't use 'this' outside of a class",
+		},
+		{
+			name:             "this in a function outside of a class",
+			source:           "fun notAMethod() { print this; }",
+			expectedErrorMsg: "can't use 'this' outside of a class",
+		},
+		{
+			name:             "return a value from an initializer",
+			source:           "class Foo { init() { return 1; } }",
+			expectedErrorMsg: "can't return a value from an initializer",
+		},
+		{
+			name:             "class inheriting from itself",
+			source:           "class Oops < Oops {}",
+			expectedErrorMsg: "a class can't inherit from itself",
+		},
+		{
+			name:             "super outside of a class",
+			source:           "super.method();",
+			expectedErrorMsg: "can't use 'super' outside of a class",
+		},
+		{
+			name:             "super in a class with no superclass",
+			source:           "class Eclair { cook() { super.cook(); } }",
+			expectedErrorMsg: "can't use 'super' in a class with no superclass",
+		},
+		{
+			name:             "top-level return",
+			source:           "return 1;",
+			expectedErrorMsg: "<stdin>:1:1: Error at 'return': can't return from top-level code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lox := &Lox{}
+			output, err := interpretSource(lox, tt.source)
+
+			assert.Error(t, err)
+			assert.True(t, lox.hadError)
+			assert.False(t, lox.hadRuntimeError)
+			assert.Contains(t, output, tt.expectedErrorMsg)
+		})
+	}
+}
+
+func TestResolver_AllowsGlobalRedeclaration(t *testing.T) {
+	lox := &Lox{}
+	output, err := interpretSource(lox, "var a = 1; var a = a + 1; print a;")
+
+	require.NoError(t, err)
+	assert.False(t, lox.hadError)
+	assert.Equal(t, "2\n", output)
+}
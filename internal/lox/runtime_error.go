@@ -0,0 +1,132 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Stable codes identifying the kind of runtime error. Host code can switch
+// on RuntimeError.Code instead of matching messages.
+const (
+	CodeOperandNotNumber   = "operand-not-number"
+	CodeOperandsNotNumbers = "operands-not-numbers"
+	CodeInvalidOperands    = "invalid-operands"
+	CodeUndefinedVariable  = "undefined-variable"
+	CodeUndefinedProperty  = "undefined-property"
+	CodeNotCallable        = "not-callable"
+	CodeArityMismatch      = "arity-mismatch"
+	CodeNotInstance        = "not-an-instance"
+	CodeSuperclassNotClass = "superclass-not-class"
+	CodeInvalidArgument    = "invalid-argument"
+	CodeTypeMismatch       = "type-mismatch"
+	CodeCanceled           = "canceled"
+	CodeStackOverflow      = "stack-overflow"
+	CodeStepLimit          = "step-limit"
+	CodeAllocationLimit    = "allocation-limit"
+	CodeIO                 = "io-error"
+	CodeIndexOutOfRange    = "index-out-of-range"
+)
+
+// CallFrame is one entry in a runtime stack trace: the function that was
+// executing and where in it execution had reached. Function is empty for
+// top-level script code.
+type CallFrame struct {
+	Function string
+	Span     Span
+}
+
+// RuntimeError is returned by Interpreter.Interpret when a script fails
+// while running. Token is where the error was raised and Code one of the
+// Code constants, or CodeRuntime for errors raised by native code. Trace
+// lists the active calls, innermost first, ending with the top-level script.
+type RuntimeError struct {
+	Err   error
+	Token Token
+	Code  string
+	Trace []CallFrame
+}
+
+func newRuntimeError(token Token, code, format string, args ...any) *RuntimeError {
+	return &RuntimeError{
+		Err:   fmt.Errorf(format, args...),
+		Token: token,
+		Code:  code,
+	}
+}
+
+// asRuntimeError returns err as a RuntimeError, wrapping it as a generic
+// one raised at token if it isn't one already.
+func asRuntimeError(err error, token Token) *RuntimeError {
+	var runtimeErr *RuntimeError
+	if errors.As(err, &runtimeErr) {
+		return runtimeErr
+	}
+	return &RuntimeError{Err: err, Token: token, Code: CodeRuntime}
+}
+
+func (e *RuntimeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RuntimeError) Unwrap() error {
+	return e.Err
+}
+
+// callFrame records an active call so a stack trace can be rebuilt if an
+// error is raised inside it.
+type callFrame struct {
+	function string
+	call     Token
+}
+
+// TypeName names the Lox type of value for use in error messages, so Go
+// types never leak to script authors.
+func TypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case *LoxClass:
+		return "class"
+	case *LoxList:
+		return "list"
+	case Object:
+		return "instance"
+	case LoxCallable:
+		return "function"
+	default:
+		return "object"
+	}
+}
+
+// callableName names a callee in stack traces.
+func callableName(callable LoxCallable) string {
+	switch c := callable.(type) {
+	case *LoxFunction:
+		return c.declaration.Name.Lexeme
+	case *LoxClass:
+		return c.Name
+	case *NativeFunction:
+		return c.Name
+	default:
+		return "<native fn>"
+	}
+}
+
+// InvalidArgument reports that argument idx (counting from zero) to the
+// native called name isn't what it expects, such as "a string", in Lox
+// terms. The error is placed at the call by the interpreter.
+func InvalidArgument(name string, idx int, expected string, argument any) *RuntimeError {
+	return &RuntimeError{
+		Err: fmt.Errorf(
+			"argument %d to %s must be %s, got %s",
+			idx+1, name, expected, TypeName(argument),
+		),
+		Code: CodeInvalidArgument,
+	}
+}
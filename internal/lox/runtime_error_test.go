@@ -0,0 +1,228 @@
+package lox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeError_StackTrace(t *testing.T) {
+	source := `fun inner() {
+  return nope;
+}
+
+fun outer() {
+  return inner();
+}
+
+class Widget {
+  init() {
+    outer();
+  }
+}
+
+Widget();`
+
+	lox := &Lox{file: "trace.lox"}
+	output, err := interpretSource(lox, source)
+
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.Equal(t, "undefined variable 'nope'", runtimeErr.Error())
+	assert.Equal(t, "nope", runtimeErr.Token.Lexeme)
+
+	type frame struct {
+		function string
+		line     int
+	}
+	actual := make([]frame, len(runtimeErr.Trace))
+	for idx, f := range runtimeErr.Trace {
+		actual[idx] = frame{f.Function, f.Span.Line}
+		assert.Equal(t, "trace.lox", f.Span.File)
+	}
+	assert.Equal(t, []frame{
+		{"inner", 2},
+		{"outer", 6},
+		{"Widget", 11},
+		{"", 15},
+	}, actual)
+
+	assert.Contains(t, output, "undefined variable 'nope'\n"+
+		"[trace.lox:2:10] in inner()\n"+
+		"[trace.lox:6:16] in outer()\n"+
+		"[trace.lox:11:11] in Widget()\n"+
+		"[trace.lox:15:8] in script\n")
+}
+
+func TestRuntimeError_TopLevelTrace(t *testing.T) {
+	lox := &Lox{}
+	_, err := interpretSource(lox, "print 1;\nprint -nil;")
+
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	require.Len(t, runtimeErr.Trace, 1)
+	assert.Equal(t, "", runtimeErr.Trace[0].Function)
+	assert.Equal(t, 2, runtimeErr.Trace[0].Span.Line)
+}
+
+func TestRuntimeError_FramesArePoppedAfterCalls(t *testing.T) {
+	lox := &Lox{}
+	_, err := interpretSource(lox, "fun f() { return 1; }\nf();\nf();\nprint -nil;")
+
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.Len(t, runtimeErr.Trace, 1, "completed calls should not appear in the trace")
+}
+
+func TestRuntimeError_Unwrap(t *testing.T) {
+	cause := errors.New("boom")
+	err := &RuntimeError{Err: cause}
+
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestRuntimeError_Codes(t *testing.T) {
+	tests := []struct {
+		name            string
+		source          string
+		expectedCode    string
+		expectedMessage string
+		expectedLexeme  string
+	}{
+		{
+			name:            "negating a string",
+			source:          "-\"a\";",
+			expectedCode:    CodeOperandNotNumber,
+			expectedMessage: "operand to - must be a number, got string",
+			expectedLexeme:  "-",
+		},
+		{
+			name:            "comparing nil and a number",
+			source:          "nil < 1;",
+			expectedCode:    CodeOperandsNotNumbers,
+			expectedMessage: "operands to < must be numbers, got nil and number",
+			expectedLexeme:  "<",
+		},
+		{
+			name:            "multiplying booleans",
+			source:          "true * false;",
+			expectedCode:    CodeOperandsNotNumbers,
+			expectedMessage: "operands to * must be numbers, got boolean and boolean",
+			expectedLexeme:  "*",
+		},
+		{
+			name:            "adding a number and a string",
+			source:          "1 + \"a\";",
+			expectedCode:    CodeInvalidOperands,
+			expectedMessage: "operands to + must be two numbers or two strings, got number and string",
+			expectedLexeme:  "+",
+		},
+		{
+			name:            "undefined variable",
+			source:          "missing;",
+			expectedCode:    CodeUndefinedVariable,
+			expectedMessage: "undefined variable 'missing'",
+			expectedLexeme:  "missing",
+		},
+		{
+			name:            "undefined property",
+			source:          "class A {} A().missing;",
+			expectedCode:    CodeUndefinedProperty,
+			expectedMessage: "undefined property 'missing'",
+			expectedLexeme:  "missing",
+		},
+		{
+			name:            "calling a number",
+			source:          "1();",
+			expectedCode:    CodeNotCallable,
+			expectedMessage: "can only call functions and classes, got number",
+			expectedLexeme:  ")",
+		},
+		{
+			name:            "wrong arity",
+			source:          "fun f(a) {} f();",
+			expectedCode:    CodeArityMismatch,
+			expectedMessage: "expected 1 arguments but got 0",
+			expectedLexeme:  ")",
+		},
+		{
+			name:            "property on a function",
+			source:          "fun f() {} f.x;",
+			expectedCode:    CodeNotInstance,
+			expectedMessage: "only instances have properties, got function",
+			expectedLexeme:  "x",
+		},
+		{
+			name:            "field on a class",
+			source:          "class A {} A.x = 1;",
+			expectedCode:    CodeNotInstance,
+			expectedMessage: "only instances have fields, got class",
+			expectedLexeme:  "x",
+		},
+		{
+			name:            "inheriting from an instance",
+			source:          "class A {} var a = A(); class B < a {}",
+			expectedCode:    CodeSuperclassNotClass,
+			expectedMessage: "superclass must be a class, got instance",
+			expectedLexeme:  "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &DiagnosticCollector{}
+			lox := &Lox{}
+			lox.SetDiagnosticSink(collector)
+
+			_, err := interpretSource(lox, tt.source)
+
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, tt.expectedCode, runtimeErr.Code)
+			assert.Equal(t, tt.expectedMessage, runtimeErr.Error())
+			assert.Equal(t, tt.expectedLexeme, runtimeErr.Token.Lexeme)
+
+			require.Len(t, collector.Diagnostics, 1)
+			assert.Equal(t, tt.expectedCode, collector.Diagnostics[0].Code)
+			for _, goType := range []string{"float64", "bool,", "<nil>", "*lox."} {
+				assert.NotContains(t, runtimeErr.Error(), goType)
+			}
+		})
+	}
+}
+
+func TestRuntimeError_WrapsPlainErrors(t *testing.T) {
+	token := NewToken(RightParen, ")", nil, 3)
+	plain := errors.New("native failure")
+
+	wrapped := asRuntimeError(plain, token)
+	assert.Equal(t, CodeRuntime, wrapped.Code)
+	assert.Equal(t, token, wrapped.Token)
+	assert.ErrorIs(t, wrapped, plain)
+
+	typed := newRuntimeError(token, CodeArityMismatch, "expected %d", 1)
+	assert.Same(t, typed, asRuntimeError(typed, NewToken(Nil, "nil", nil, 9)))
+}
+
+func TestTypeName(t *testing.T) {
+	class := NewLoxClass("A", nil, map[string]*LoxFunction{})
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{nil, "nil"},
+		{true, "boolean"},
+		{1.5, "number"},
+		{"s", "string"},
+		{class, "class"},
+		{NewLoxInstance(class), "instance"},
+		{NewLoxFunction(FunctionStmt{}, nil, false), "function"},
+		{struct{}{}, "object"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, TypeName(tt.value))
+	}
+}
@@ -3,6 +3,7 @@ package lox
 import (
 	"fmt"
 	"strconv"
+	"unicode/utf8"
 )
 
 // Scanner performs lexical analysis on Lox source code.
@@ -10,8 +11,13 @@ type Scanner struct {
 	source []rune  // The source code as runes for Unicode support
 	tokens []Token // The scanned tokens
 	lox    *Lox    // Reference to the interpreter for error reporting
+	file   *Source // Where the source came from, recorded on every token
 
 	start, current, line int // Position tracking in the source
+
+	lineStart              int // Rune index of the first rune on the current line
+	startByte, currentByte int // Byte offsets matching start and current
+	startLine, startColumn int // Where the token being scanned began
 }
 
 // NewScanner creates a new Scanner for the given source code.
@@ -19,6 +25,7 @@ func NewScanner(source string, lox *Lox) Scanner {
 	return Scanner{
 		source: []rune(source),
 		lox:    lox,
+		file:   &Source{File: lox.file, Text: source},
 		line:   1,
 	}
 }
@@ -28,10 +35,16 @@ func NewScanner(source string, lox *Lox) Scanner {
 func (s *Scanner) ScanTokens() []Token {
 	for !s.isAtEnd() {
 		s.start = s.current
+		s.startByte = s.currentByte
+		s.startLine = s.line
+		s.startColumn = s.column()
 		s.scanToken()
 	}
 
-	s.tokens = append(s.tokens, NewToken(EOF, "", nil, s.line))
+	eof := NewToken(EOF, "", nil, s.line)
+	eof.Column, eof.Start, eof.End = s.column(), s.currentByte, s.currentByte
+	eof.Source = s.file
+	s.tokens = append(s.tokens, eof)
 	return s.tokens
 }
 
@@ -90,10 +103,8 @@ func (s *Scanner) scanToken() {
 		} else {
 			s.addToken(Slash)
 		}
-	case ' ', '\r', '\t':
+	case ' ', '\r', '\t', '\n':
 		break
-	case '\n':
-		s.line++
 	case '"':
 		s.scanString()
 	default:
@@ -102,21 +113,20 @@ func (s *Scanner) scanToken() {
 		} else if isAlpha(r) {
 			s.scanIdentifier()
 		} else {
-			s.lox.error(s.line, fmt.Sprintf("Unexpected character %q", r))
+			s.error(CodeUnexpectedCharacter, s.currentSpan(), fmt.Sprintf("Unexpected character %q", r))
 		}
 	}
 }
 
 func (s *Scanner) scanString() {
 	for s.peek() != '"' && !s.isAtEnd() {
-		if s.peek() == '\n' {
-			s.line++
-		}
 		s.advance()
 	}
 
 	if s.isAtEnd() {
-		s.lox.error(s.line, "Unterminated string")
+		// Point at the end of input, where the closing quote is missing.
+		end := s.lox.span(s.file, s.line, s.column(), s.currentByte, s.currentByte, "")
+		s.error(CodeUnterminatedString, end, "Unterminated string")
 		return
 	}
 
@@ -143,7 +153,7 @@ func (s *Scanner) scanNumber() {
 	n, err := strconv.ParseFloat(floatStr, 64)
 	if err != nil {
 		// NOTE: We should never get here because of the scanner's pre-validation
-		s.lox.error(s.line, fmt.Sprintf("Unparseable float: %s", floatStr))
+		s.error(CodeInvalidNumber, s.currentSpan(), fmt.Sprintf("Unparseable float: %s", floatStr))
 		return
 	}
 	s.addTokenWithLiteral(Number, n)
@@ -162,9 +172,16 @@ func (s *Scanner) scanIdentifier() {
 	s.addToken(tokenType)
 }
 
+// advance consumes the next rune, keeping the line, column and byte offset
+// bookkeeping in step with it.
 func (s *Scanner) advance() rune {
 	r := s.source[s.current]
 	s.current++
+	s.currentByte += utf8.RuneLen(r)
+	if r == '\n' {
+		s.line++
+		s.lineStart = s.current
+	}
 	return r
 }
 
@@ -175,10 +192,24 @@ func (s *Scanner) match(expected rune) bool {
 	if s.source[s.current] != expected {
 		return false
 	}
-	s.current++
+	s.advance()
 	return true
 }
 
+func (s *Scanner) error(code string, span Span, message string) {
+	s.lox.error(PhaseScan, code, span, message)
+}
+
+// currentSpan covers the token scanned so far.
+func (s *Scanner) currentSpan() Span {
+	return s.lox.span(s.file, s.startLine, s.startColumn, s.startByte, s.currentByte, string(s.source[s.start:s.current]))
+}
+
+// column returns the 1-based column of the next rune to be scanned.
+func (s *Scanner) column() int {
+	return s.current - s.lineStart + 1
+}
+
 func (s *Scanner) peek() rune {
 	var r rune // Zero value is '\0'
 	if s.isAtEnd() {
@@ -201,7 +232,10 @@ func (s *Scanner) addToken(tokenType TokenType) {
 
 func (s *Scanner) addTokenWithLiteral(tokenType TokenType, literal any) {
 	lexeme := s.source[s.start:s.current]
-	s.tokens = append(s.tokens, NewToken(tokenType, string(lexeme), literal, s.line))
+	token := NewToken(tokenType, string(lexeme), literal, s.startLine)
+	token.Column, token.Start, token.End = s.startColumn, s.startByte, s.currentByte
+	token.Source = s.file
+	s.tokens = append(s.tokens, token)
 }
 
 func (s *Scanner) isAtEnd() bool {
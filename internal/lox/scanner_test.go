@@ -339,7 +339,7 @@ func TestScanner_scanTokens(t *testing.T) {
 			expectedTypes:  []TokenType{EOF},
 			expectedCount:  1,
 			expectHadError: true,
-			expectedError:  "[line 1] Error : Unterminated string",
+			expectedError:  "<stdin>:1:7: Error: Unterminated string",
 		},
 		{
 			name:           "unterminated multiline string",
@@ -347,7 +347,7 @@ func TestScanner_scanTokens(t *testing.T) {
 			expectedTypes:  []TokenType{EOF},
 			expectedCount:  1,
 			expectHadError: true,
-			expectedError:  "[line 2] Error : Unterminated string",
+			expectedError:  "<stdin>:2:6: Error: Unterminated string",
 		},
 		{
 			name:           "integer number",
@@ -983,3 +983,48 @@ func TestScanner_scanToken_whitespace(t *testing.T) {
 		})
 	}
 }
+
+func TestScanner_tokenPositions(t *testing.T) {
+	source := "var cafe = \"é\";\n  print cafe;"
+	lox := &Lox{}
+	scanner := NewScanner(source, lox)
+	tokens := scanner.ScanTokens()
+
+	type position struct {
+		lexeme             string
+		line, column       int
+		startByte, endByte int
+	}
+	expected := []position{
+		{"var", 1, 1, 0, 3},
+		{"cafe", 1, 5, 4, 8},
+		{"=", 1, 10, 9, 10},
+		{"\"é\"", 1, 12, 11, 15},
+		{";", 1, 15, 15, 16},
+		{"print", 2, 3, 19, 24},
+		{"cafe", 2, 9, 25, 29},
+		{";", 2, 13, 29, 30},
+		{"", 2, 14, 30, 30},
+	}
+
+	actual := make([]position, len(tokens))
+	for idx, token := range tokens {
+		actual[idx] = position{token.Lexeme, token.Line, token.Column, token.Start, token.End}
+	}
+	assert.Equal(t, expected, actual)
+
+	for _, token := range tokens {
+		assert.Equal(t, token.Lexeme, source[token.Start:token.End], "byte offsets should slice out the lexeme")
+	}
+}
+
+func TestScanner_multilineStringPosition(t *testing.T) {
+	lox := &Lox{}
+	scanner := NewScanner("x \"one\ntwo\" y", lox)
+	tokens := scanner.ScanTokens()
+
+	assert.Equal(t, 1, tokens[1].Line, "a string token starts on its opening line")
+	assert.Equal(t, 3, tokens[1].Column)
+	assert.Equal(t, 2, tokens[2].Line)
+	assert.Equal(t, 6, tokens[2].Column)
+}
@@ -0,0 +1,164 @@
+package lox
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used when a SnippetSink is colorized.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+)
+
+// SnippetSink writes each diagnostic in the same text format as TextSink,
+// followed by the offending source line and its neighbours with the span
+// underlined:
+//
+//	script.lox:2:11: Error at 'x': undefined variable
+//	  |
+//	1 | var a = 1;
+//	2 | print a + x;
+//	  |           ^
+//	3 | print a;
+type SnippetSink struct {
+	w      io.Writer
+	lines  []string
+	color  bool
+	header *TextSink
+
+	// ContextLines is how many lines to show on each side of the
+	// offending one.
+	ContextLines int
+}
+
+// NewSnippetSink creates a SnippetSink that renders spans against source
+// and writes to w, using ANSI colors if color is true.
+func NewSnippetSink(w io.Writer, source string, color bool) *SnippetSink {
+	var lines []string
+	if source != "" {
+		// A final newline ends the last line rather than starting another.
+		lines = strings.Split(strings.TrimSuffix(source, "\n"), "\n")
+	}
+	return &SnippetSink{
+		w:            w,
+		lines:        lines,
+		color:        color,
+		header:       NewTextSink(w),
+		ContextLines: 1,
+	}
+}
+
+// Report writes d followed by its source snippet. Diagnostics whose span
+// falls outside the source get the header alone.
+func (s *SnippetSink) Report(d Diagnostic) {
+	headerColor := ansiRed
+	if d.Severity == SeverityWarning {
+		headerColor = ansiYellow
+	}
+	s.write(ansiBold + headerColor)
+	s.header.Report(d)
+	s.write(ansiReset)
+
+	if d.Span.Line < 1 || d.Span.Line > len(s.lines) {
+		return
+	}
+
+	first := max(1, d.Span.Line-s.ContextLines)
+	last := min(len(s.lines), d.Span.Line+s.ContextLines)
+	width := len(strconv.Itoa(last))
+	gutter := strings.Repeat(" ", width) + " |"
+
+	s.writeGutter(gutter + "\n")
+	for line := first; line <= last; line++ {
+		text := strings.TrimRight(s.lines[line-1], "\r")
+		s.writeGutter(fmt.Sprintf("%*d |", width, line))
+		if text != "" {
+			s.write(" " + text)
+		}
+		s.write("\n")
+
+		if line == d.Span.Line {
+			s.writeGutter(gutter)
+			s.write(" " + indentTo(text, d.Span.Column))
+			s.write(headerColor + underline(text, d.Span) + ansiReset)
+			s.write("\n")
+		}
+	}
+}
+
+// write emits text, dropping ANSI sequences when color is disabled.
+func (s *SnippetSink) write(text string) {
+	if !s.color {
+		text = stripANSI(text)
+	}
+	_, _ = io.WriteString(s.w, text)
+}
+
+func (s *SnippetSink) writeGutter(text string) {
+	s.write(ansiBold + ansiBlue + text + ansiReset)
+}
+
+// indentTo returns whitespace reaching the 1-based rune column in line,
+// keeping any tabs so the underline stays aligned.
+func indentTo(line string, column int) string {
+	var b strings.Builder
+	idx := 1
+	for _, r := range line {
+		if idx >= column {
+			break
+		}
+		if r == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+		idx++
+	}
+	for ; idx < column; idx++ {
+		b.WriteRune(' ')
+	}
+	return b.String()
+}
+
+// underline draws a caret under the first rune of the span and tildes under
+// the rest of it, stopping at the end of the line.
+func underline(line string, span Span) string {
+	length := utf8.RuneCountInString(span.Text)
+	if idx := strings.IndexByte(span.Text, '\n'); idx >= 0 {
+		length = utf8.RuneCountInString(span.Text[:idx])
+	}
+	remaining := utf8.RuneCountInString(line) - (span.Column - 1)
+	length = min(length, remaining)
+	if length < 1 {
+		return "^"
+	}
+	return "^" + strings.Repeat("~", length-1)
+}
+
+func stripANSI(text string) string {
+	for _, code := range []string{ansiReset, ansiBold, ansiRed, ansiYellow, ansiBlue} {
+		text = strings.ReplaceAll(text, code, "")
+	}
+	return text
+}
+
+// useColor reports whether output to f should be colorized: only when it
+// is a terminal, and never when the NO_COLOR convention asks otherwise.
+func useColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
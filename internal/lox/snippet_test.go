@@ -0,0 +1,138 @@
+package lox
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnippetSink(t *testing.T) {
+	source := "var a = 1;\nprint a + bb;\nprint a;\nprint 2;"
+
+	tests := []struct {
+		name       string
+		diagnostic Diagnostic
+		expected   string
+	}{
+		{
+			name: "underlines the token with surrounding context",
+			diagnostic: Diagnostic{
+				Phase:   PhaseRuntime,
+				Span:    Span{File: "s.lox", Line: 2, Column: 11, Text: "bb"},
+				Message: "undefined variable 'bb'",
+			},
+			expected: "undefined variable 'bb'\n" +
+				"[s.lox:2:11]\n" +
+				"  |\n" +
+				"1 | var a = 1;\n" +
+				"2 | print a + bb;\n" +
+				"  |           ^~\n" +
+				"3 | print a;\n",
+		},
+		{
+			name: "context is clipped at the start of the source",
+			diagnostic: Diagnostic{
+				Phase:   PhaseParse,
+				Span:    Span{Line: 1, Column: 1, Text: "var"},
+				Message: "oops",
+			},
+			expected: "<stdin>:1:1: Error at 'var': oops\n" +
+				"  |\n" +
+				"1 | var a = 1;\n" +
+				"  | ^~~\n" +
+				"2 | print a + bb;\n",
+		},
+		{
+			name: "zero-width span at end of line gets a single caret",
+			diagnostic: Diagnostic{
+				Phase:   PhaseParse,
+				Span:    Span{Line: 4, Column: 9},
+				Message: "expect ';' after value",
+			},
+			expected: "<stdin>:4:9: Error at end: expect ';' after value\n" +
+				"  |\n" +
+				"3 | print a;\n" +
+				"4 | print 2;\n" +
+				"  |         ^\n",
+		},
+		{
+			name: "span outside the source prints only the header",
+			diagnostic: Diagnostic{
+				Phase:   PhaseScan,
+				Span:    Span{Line: 12, Column: 1},
+				Message: "Unexpected character",
+			},
+			expected: "<stdin>:12:1: Error: Unexpected character\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			sink := NewSnippetSink(&buf, source, false)
+
+			sink.Report(tt.diagnostic)
+
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestSnippetSink_Tabs(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewSnippetSink(&buf, "\tprint x;", false)
+	sink.ContextLines = 0
+
+	sink.Report(Diagnostic{
+		Phase:   PhaseRuntime,
+		Span:    Span{Line: 1, Column: 8, Text: "x"},
+		Message: "undefined variable 'x'",
+	})
+
+	assert.Equal(t, "undefined variable 'x'\n[<stdin>:1:8]\n  |\n1 | \tprint x;\n  | \t      ^\n", buf.String())
+}
+
+func TestSnippetSink_TrailingNewline(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewSnippetSink(&buf, "var a = \"x\"; a();\n", false)
+
+	sink.Report(Diagnostic{
+		Phase:   PhaseRuntime,
+		Span:    Span{Line: 1, Column: 16, Text: "("},
+		Message: "can only call functions and classes, got string",
+	})
+
+	assert.Equal(t,
+		"can only call functions and classes, got string\n[<stdin>:1:16]\n"+
+			"  |\n1 | var a = \"x\"; a();\n  |                ^\n",
+		buf.String(),
+	)
+}
+
+func TestSnippetSink_Color(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewSnippetSink(&buf, "print x;", true)
+
+	sink.Report(Diagnostic{
+		Phase:   PhaseRuntime,
+		Span:    Span{Line: 1, Column: 7, Text: "x"},
+		Message: "undefined variable 'x'",
+	})
+
+	output := buf.String()
+	assert.Contains(t, output, ansiBold+ansiRed+"undefined variable 'x'")
+	assert.Contains(t, output, ansiRed+"^"+ansiReset)
+	assert.Equal(t, "undefined variable 'x'\n[<stdin>:1:7]\n  |\n1 | print x;\n  |       ^\n", stripANSI(output))
+}
+
+func TestLox_reportIncludesSnippet(t *testing.T) {
+	lox := &Lox{}
+
+	output, _ := captureOutput(func() error {
+		lox.run("var a = 1;\nprint a +;")
+		return nil
+	})
+
+	assert.Contains(t, output, "2 | print a +;\n  |          ^\n")
+}
@@ -0,0 +1,99 @@
+package lox
+
+type StmtVisitor interface {
+	VisitBlockStmt(s BlockStmt)
+	VisitClassStmt(s ClassStmt)
+	VisitExpressionStmt(s ExpressionStmt)
+	VisitFunctionStmt(s FunctionStmt)
+	VisitIfStmt(s IfStmt)
+	VisitPrintStmt(s PrintStmt)
+	VisitReturnStmt(s ReturnStmt)
+	VisitVarStmt(s VarStmt)
+	VisitWhileStmt(s WhileStmt)
+}
+
+type Stmt interface {
+	Accept(v StmtVisitor)
+}
+
+type BlockStmt struct {
+	Statements []Stmt
+}
+
+func (s BlockStmt) Accept(v StmtVisitor) {
+	v.VisitBlockStmt(s)
+}
+
+type ClassStmt struct {
+	Name       Token
+	Superclass *Variable
+	Methods    []FunctionStmt
+}
+
+func (s ClassStmt) Accept(v StmtVisitor) {
+	v.VisitClassStmt(s)
+}
+
+type ExpressionStmt struct {
+	Expr Expr
+}
+
+func (s ExpressionStmt) Accept(v StmtVisitor) {
+	v.VisitExpressionStmt(s)
+}
+
+type FunctionStmt struct {
+	Name   Token
+	Params []Token
+	Body   []Stmt
+}
+
+func (s FunctionStmt) Accept(v StmtVisitor) {
+	v.VisitFunctionStmt(s)
+}
+
+type IfStmt struct {
+	Condition  Expr
+	ThenBranch Stmt
+	ElseBranch Stmt
+}
+
+func (s IfStmt) Accept(v StmtVisitor) {
+	v.VisitIfStmt(s)
+}
+
+type PrintStmt struct {
+	Expr Expr
+}
+
+func (s PrintStmt) Accept(v StmtVisitor) {
+	v.VisitPrintStmt(s)
+}
+
+type ReturnStmt struct {
+	Keyword Token
+	Value   Expr
+}
+
+func (s ReturnStmt) Accept(v StmtVisitor) {
+	v.VisitReturnStmt(s)
+}
+
+type VarStmt struct {
+	Name        Token
+	Initializer Expr
+}
+
+func (s VarStmt) Accept(v StmtVisitor) {
+	v.VisitVarStmt(s)
+}
+
+type WhileStmt struct {
+	Keyword   Token
+	Condition Expr
+	Body      Stmt
+}
+
+func (s WhileStmt) Accept(v StmtVisitor) {
+	v.VisitWhileStmt(s)
+}
@@ -0,0 +1,54 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// stringify renders a Lox value the way Lox code sees it, for print, the
+// REPL and str(). It follows the reference implementation: nil reads as
+// "nil" and numbers print like Java doubles without a trailing ".0".
+func stringify(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case float64:
+		return formatNumber(v)
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatNumber formats n as Java's Double.toString would, dropping the
+// ".0" from integral values: decimal notation between 1e-3 and 1e7 and
+// scientific notation such as 1.5E21 outside it.
+func formatNumber(n float64) string {
+	switch {
+	case math.IsNaN(n):
+		return "NaN"
+	case math.IsInf(n, 1):
+		return "Infinity"
+	case math.IsInf(n, -1):
+		return "-Infinity"
+	}
+
+	if abs := math.Abs(n); abs == 0 || (abs >= 1e-3 && abs < 1e7) {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(n, 'e', -1, 64), "e")
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	sign := ""
+	if exponent[0] == '-' {
+		sign = "-"
+	}
+	return mantissa + "E" + sign + strings.TrimLeft(exponent[1:], "0")
+}
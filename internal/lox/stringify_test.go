@@ -0,0 +1,62 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringify(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"nil", nil, "nil"},
+		{"true", true, "true"},
+		{"false", false, "false"},
+		{"string", "hi", "hi"},
+		{"integral number", 3.0, "3"},
+		{"negative integral number", -42.0, "-42"},
+		{"zero", 0.0, "0"},
+		{"fraction", 2.5, "2.5"},
+		{"small fraction", 0.001, "0.001"},
+		{"largest plain number", 9999999.0, "9999999"},
+		{"large number", 1e7, "1.0E7"},
+		{"very large number", 1e21, "1.0E21"},
+		{"large fraction", 1.5e21, "1.5E21"},
+		{"tiny number", 0.0001, "1.0E-4"},
+		{"tiny fraction", -1.25e-10, "-1.25E-10"},
+		{"list", &LoxList{elements: []any{"a", 1.0, nil, true}}, "[a, 1, nil, true]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, stringify(tt.value))
+		})
+	}
+}
+
+func TestStringify_Objects(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"function", "fun greet() {} print greet;", "<fn greet>\n"},
+		{"method", "class A { m() {} } print A().m;", "<fn m>\n"},
+		{"class", "class A {} print A;", "A\n"},
+		{"instance", "class A {} print A();", "A instance\n"},
+		{"native", "print len;", "<native fn len>\n"},
+		{"arithmetic", "print 10 / 4; print 2 * 3; print 1000000 * 10000000;", "2.5\n6\n1.0E13\n"},
+		{"str", "print str(nil) + str(1) + str(true);", "nil1true\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := interpretSource(&Lox{}, tt.source)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
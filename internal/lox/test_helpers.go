@@ -1,6 +1,7 @@
 package lox
 
 import (
+	"fmt"
 	"io"
 	"os"
 )
@@ -24,3 +25,21 @@ func captureOutput(f func() error) (string, error) {
 	}
 	return string(out), err
 }
+
+func interpretSource(lox *Lox, source string) (string, error) {
+	return captureOutput(func() error {
+		scanner := NewScanner(source, lox)
+		parser := NewParser(scanner.ScanTokens(), lox)
+		statements, err := parser.Parse()
+		if err != nil {
+			return err
+		}
+		interpreter := NewInterpreter(lox)
+		resolver := NewResolver(&interpreter, lox)
+		resolver.Resolve(statements)
+		if lox.hadError {
+			return fmt.Errorf("resolution failed")
+		}
+		return interpreter.Interpret(statements)
+	})
+}
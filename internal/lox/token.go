@@ -6,7 +6,11 @@ type Token struct {
 	Lexeme    string    // The raw text of the token
 	Literal   any       // The literal value (for numbers, strings, etc.)
 	TokenType TokenType // The type of token
-	Line      int       // The line number where the token appears
+	Line      int       // The line number where the token starts
+	Column    int       // The 1-based column, in runes, where the token starts
+	Start     int       // The byte offset of the token's first byte
+	End       int       // The byte offset just past the token's last byte
+	Source    *Source   // The source the token was scanned from, if any
 }
 
 // NewToken creates a new Token with the given properties.
@@ -0,0 +1,105 @@
+package lox
+
+import (
+	"fmt"
+	"reflect"
+
+	interp "github.com/mikowitz/go-lox/internal/lox"
+)
+
+// boundObject exposes a Go struct to scripts as an instance. Its exported
+// fields are properties and its exported methods, including those on the
+// pointer receiver, are callable methods.
+type boundObject struct {
+	v reflect.Value // always a non-nil pointer to a struct
+}
+
+// bind wraps ptr, a non-nil pointer to a struct.
+func bind(ptr reflect.Value) *boundObject {
+	return &boundObject{v: ptr}
+}
+
+// Get returns the named field converted to a Lox value, or else the named
+// method as a native function. Fields whose types Lox can't represent are
+// an error rather than silently hidden.
+func (o *boundObject) Get(name interp.Token) (any, error) {
+	if field, ok := o.field(name.Lexeme); ok {
+		value, ok := fromGo(field)
+		if !ok {
+			return nil, &RuntimeError{
+				Err:   fmt.Errorf("property '%s' can't be read from Lox", name.Lexeme),
+				Token: name,
+				Code:  CodeTypeMismatch,
+			}
+		}
+		return value, nil
+	}
+
+	if method := o.v.MethodByName(name.Lexeme); method.IsValid() {
+		arity := method.Type().NumIn()
+		call, err := wrapFunc(name.Lexeme, method.Interface(), arity)
+		if err != nil {
+			return nil, &RuntimeError{
+				Err:   fmt.Errorf("method '%s' can't be called from Lox", name.Lexeme),
+				Token: name,
+				Code:  CodeTypeMismatch,
+			}
+		}
+		return interp.NewNativeFunction(name.Lexeme, arity, call), nil
+	}
+
+	return nil, &RuntimeError{
+		Err:   fmt.Errorf("undefined property '%s'", name.Lexeme),
+		Token: name,
+		Code:  CodeUndefinedProperty,
+	}
+}
+
+// Set converts value to the named field's type and stores it. Unlike Lox
+// instances, bound objects can't gain new fields.
+func (o *boundObject) Set(name interp.Token, value any) error {
+	field, ok := o.field(name.Lexeme)
+	if !ok || !field.CanSet() {
+		return &RuntimeError{
+			Err:   fmt.Errorf("undefined property '%s'", name.Lexeme),
+			Token: name,
+			Code:  CodeUndefinedProperty,
+		}
+	}
+
+	converted, ok := toGo(value, field.Type())
+	if !ok {
+		return &RuntimeError{
+			Err: fmt.Errorf(
+				"property '%s' must be %s, got %s",
+				name.Lexeme, loxTypeFor(field.Type()), interp.TypeName(value),
+			),
+			Token: name,
+			Code:  CodeTypeMismatch,
+		}
+	}
+	field.Set(converted)
+	return nil
+}
+
+// field looks up an exported field, including one promoted from an
+// embedded struct. Fields behind a nil embedded pointer don't exist.
+func (o *boundObject) field(name string) (reflect.Value, bool) {
+	structField, ok := o.v.Elem().Type().FieldByName(name)
+	if !ok || !structField.IsExported() {
+		return reflect.Value{}, false
+	}
+	field, err := o.v.Elem().FieldByIndexErr(structField.Index)
+	if err != nil || !field.CanInterface() {
+		return reflect.Value{}, false
+	}
+	return field, true
+}
+
+func (o *boundObject) String() string {
+	name := o.v.Elem().Type().Name()
+	if name == "" {
+		name = "object"
+	}
+	return name + " instance"
+}
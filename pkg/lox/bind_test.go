@@ -0,0 +1,251 @@
+package lox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLimits struct {
+	Retries int
+}
+
+type testConfig struct {
+	testLimits
+	Host    string
+	Port    uint16
+	Debug   bool
+	Tags    []string
+	Backup  *testConfig
+	secret  string
+	Timeout float64
+	Err     error
+}
+
+func (c testConfig) Address() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+func (c *testConfig) Bump(by int) int {
+	c.Port += uint16(by)
+	return int(c.Port)
+}
+
+func (c *testConfig) Clone() testConfig {
+	return *c
+}
+
+func (c *testConfig) SameHost(other *testConfig) bool {
+	return c.Host == other.Host
+}
+
+func (c *testConfig) Lookup(m map[string]string) string {
+	return m[c.Host]
+}
+
+func newTestConfig() *testConfig {
+	return &testConfig{
+		testLimits: testLimits{Retries: 3},
+		Host:       "localhost",
+		Port:       8080,
+		secret:     "hunter2",
+		Backup:     &testConfig{Host: "backup"},
+	}
+}
+
+func TestEngine_Bind(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected Value
+	}{
+		{"string field", "config.Host;", "localhost"},
+		{"numeric field", "config.Port;", 8080.0},
+		{"promoted field", "config.Retries;", 3.0},
+		{"nested pointer", "config.Backup.Host;", "backup"},
+		{"nil pointer", "config.Backup.Backup;", nil},
+		{"value method", "config.Address();", "localhost:8080"},
+		{"pointer method", "config.Bump(2);", 8082.0},
+		{"method as a value", "var address = config.Address; address();", "localhost:8080"},
+		{"struct result", "config.Clone().Host;", "localhost"},
+		{"object argument", "config.SameHost(config.Backup);", false},
+		{"assigning through an alias", "var c = config; c.Debug = !c.Debug; c.Debug;", true},
+		{"nil for an interface field", "config.Err = nil; config.Err;", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(io.Discard, io.Discard)
+			require.NoError(t, engine.Bind("config", newTestConfig()))
+
+			value, err := engine.Eval(context.Background(), tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestEngine_Bind_WritesThroughPointers(t *testing.T) {
+	var stdout bytes.Buffer
+	engine := newTestEngine(&stdout, io.Discard)
+	config := newTestConfig()
+	require.NoError(t, engine.Bind("config", config))
+
+	_, err := engine.Eval(context.Background(), `
+config.Host = "example.com";
+config.Port = 443;
+config.Retries = 5;
+config.Timeout = 1.5;
+config.Backup.Host = "standby";
+config.Bump(1);
+print config;
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", config.Host)
+	assert.Equal(t, uint16(444), config.Port)
+	assert.Equal(t, 5, config.Retries)
+	assert.Equal(t, 1.5, config.Timeout)
+	assert.Equal(t, "standby", config.Backup.Host)
+	assert.Equal(t, "testConfig instance\n", stdout.String())
+}
+
+func TestEngine_Bind_CopiesStructs(t *testing.T) {
+	engine := newTestEngine(io.Discard, io.Discard)
+	config := *newTestConfig()
+	require.NoError(t, engine.Bind("config", config))
+
+	value, err := engine.Eval(context.Background(), "config.Port = 1; config.Port;")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+	assert.Equal(t, uint16(8080), config.Port)
+}
+
+func TestEngine_Bind_RuntimeErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		source          string
+		expectedCode    string
+		expectedMessage string
+	}{
+		{
+			name:            "wrong field type",
+			source:          "config.Port = \"80\";",
+			expectedCode:    CodeTypeMismatch,
+			expectedMessage: "property 'Port' must be a non-negative integer, got string",
+		},
+		{
+			name:            "field out of range",
+			source:          "config.Port = 70000;",
+			expectedCode:    CodeTypeMismatch,
+			expectedMessage: "property 'Port' must be a non-negative integer, got number",
+		},
+		{
+			name:            "nil for a boolean",
+			source:          "config.Debug = nil;",
+			expectedCode:    CodeTypeMismatch,
+			expectedMessage: "property 'Debug' must be a boolean, got nil",
+		},
+		{
+			name:            "value for an interface it doesn't implement",
+			source:          "config.Err = 1;",
+			expectedCode:    CodeTypeMismatch,
+			expectedMessage: "property 'Err' must be a value implementing error, got number",
+		},
+		{
+			name:            "unsupported field type",
+			source:          "config.Tags;",
+			expectedCode:    CodeTypeMismatch,
+			expectedMessage: "property 'Tags' can't be read from Lox",
+		},
+		{
+			name:            "unsupported method",
+			source:          "config.Lookup;",
+			expectedCode:    CodeTypeMismatch,
+			expectedMessage: "method 'Lookup' can't be called from Lox",
+		},
+		{
+			name:            "unexported field",
+			source:          "config.secret;",
+			expectedCode:    CodeUndefinedProperty,
+			expectedMessage: "undefined property 'secret'",
+		},
+		{
+			name:            "new field",
+			source:          "config.extra = 1;",
+			expectedCode:    CodeUndefinedProperty,
+			expectedMessage: "undefined property 'extra'",
+		},
+		{
+			name:            "wrong argument type",
+			source:          "config.SameHost(\"backup\");",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 1 to SameHost must be a testConfig instance, got string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(io.Discard, io.Discard)
+			require.NoError(t, engine.Bind("config", newTestConfig()))
+
+			_, err := engine.Eval(context.Background(), tt.source)
+
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, tt.expectedCode, runtimeErr.Code)
+			assert.Equal(t, tt.expectedMessage, runtimeErr.Error())
+			assert.Equal(t, 1, runtimeErr.Token.Line)
+		})
+	}
+}
+
+func TestEngine_Bind_RejectsNonStructs(t *testing.T) {
+	var nilConfig *testConfig
+	engine := newTestEngine(io.Discard, io.Discard)
+
+	for _, value := range []any{nil, 42, "s", nilConfig, &[]int{}} {
+		assert.Error(t, engine.Bind("bad", value))
+	}
+}
+
+func TestEngine_LoxValuesRoundTrip(t *testing.T) {
+	engine := newTestEngine(io.Discard, io.Discard)
+	require.NoError(t, engine.Define("id", func(v Value) Value { return v }, 1))
+	require.NoError(t, engine.Define("rawID", func(args []Value) (Value, error) { return args[0], nil }, 1))
+	require.NoError(t, engine.Bind("config", newTestConfig()))
+	_, err := engine.Eval(context.Background(), `
+fun f() { return "called"; }
+class A { init() { this.x = 1; } }
+var a = A();
+`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		source   string
+		expected Value
+	}{
+		{"id(f)();", "called"},
+		{"rawID(f)();", "called"},
+		{"id(A)().x;", 1.0},
+		{"id(a).x;", 1.0},
+		{"rawID(a) == a;", true},
+		{"id(len)(\"abc\");", 3.0},
+		{"id(split(\"a b\", \" \")).length;", 2.0},
+		{"id(config).Host;", "localhost"},
+		{"rawID(config) == config;", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			value, err := engine.Eval(context.Background(), tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	interp "github.com/mikowitz/go-lox/internal/lox"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	float64Type = reflect.TypeOf(float64(0))
+)
+
+// wrapFunc adapts fn to the signature natives are called with, converting
+// arguments and results between Lox values and fn's parameter and result
+// types.
+func wrapFunc(name string, fn any, arity int) (func([]Value) (Value, error), error) {
+	if raw, ok := fn.(func([]Value) (Value, error)); ok {
+		if arity < 0 {
+			return nil, fmt.Errorf("%s: arity must not be negative, got %d", name, arity)
+		}
+		return func(arguments []Value) (Value, error) {
+			value, err := raw(arguments)
+			if err != nil {
+				return nil, err
+			}
+			// reflect.ValueOf(nil) is the zero Value, which fromResult maps
+			// to nil.
+			return fromResult(name, reflect.ValueOf(value))
+		}, nil
+	}
+
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, fmt.Errorf("%s: expected a function, got %T", name, fn)
+	}
+	t := v.Type()
+	if t.IsVariadic() {
+		return nil, fmt.Errorf("%s: variadic functions are not supported", name)
+	}
+	if t.NumIn() != arity {
+		return nil, fmt.Errorf("%s: function takes %d parameters but arity is %d", name, t.NumIn(), arity)
+	}
+	for idx := range t.NumIn() {
+		if !convertible(t.In(idx)) {
+			return nil, fmt.Errorf("%s: unsupported parameter type %s", name, t.In(idx))
+		}
+	}
+	if err := checkResults(t); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return func(arguments []Value) (Value, error) {
+		in := make([]reflect.Value, len(arguments))
+		for idx, argument := range arguments {
+			arg, ok := toGo(argument, t.In(idx))
+			if !ok {
+				return nil, interp.InvalidArgument(name, idx, loxTypeFor(t.In(idx)), argument)
+			}
+			in[idx] = arg
+		}
+		return fromResults(name, v.Call(in))
+	}, nil
+}
+
+// checkResults accepts functions returning nothing, a value, an error, or a
+// value followed by an error.
+func checkResults(t reflect.Type) error {
+	switch t.NumOut() {
+	case 0:
+		return nil
+	case 1:
+		return nil
+	case 2:
+		if t.Out(1) != errorType {
+			return fmt.Errorf("second result must be an error, got %s", t.Out(1))
+		}
+		return nil
+	default:
+		return fmt.Errorf("functions may return at most a value and an error, got %d results", t.NumOut())
+	}
+}
+
+func fromResults(name string, out []reflect.Value) (Value, error) {
+	if len(out) > 0 && out[len(out)-1].IsValid() && out[len(out)-1].Type() == errorType {
+		if err, _ := out[len(out)-1].Interface().(error); err != nil {
+			return nil, err
+		}
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return fromResult(name, out[0])
+}
+
+// fromResult converts a native's result to a Lox value.
+func fromResult(name string, result reflect.Value) (Value, error) {
+	value, ok := fromGo(result)
+	if !ok {
+		return nil, fmt.Errorf("%s returned a %s, which has no Lox equivalent", name, result.Type())
+	}
+	return value, nil
+}
+
+// convertible reports whether Lox values can be converted to t.
+func convertible(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Struct:
+		return true
+	case reflect.Pointer:
+		return t.Elem().Kind() == reflect.Struct
+	default:
+		return false
+	}
+}
+
+// toGo converts a Lox value to t. Numbers convert to integer types only
+// when they are whole and in range, and bound Go values convert back to the
+// struct or pointer they wrap.
+func toGo(value Value, t reflect.Type) (reflect.Value, bool) {
+	switch t.Kind() {
+	case reflect.Interface:
+		if value == nil {
+			return reflect.Zero(t), true
+		}
+		v := reflect.ValueOf(value)
+		if object, ok := value.(*boundObject); ok {
+			v = object.v
+		}
+		// Non-empty interfaces, such as error, only accept values that
+		// implement them.
+		return v, v.Type().AssignableTo(t)
+	case reflect.Pointer:
+		if value == nil {
+			return reflect.Zero(t), true
+		}
+		object, ok := value.(*boundObject)
+		if !ok || object.v.Type() != t {
+			return reflect.Value{}, false
+		}
+		return object.v, true
+	case reflect.Struct:
+		object, ok := value.(*boundObject)
+		if !ok || object.v.Elem().Type() != t {
+			return reflect.Value{}, false
+		}
+		return object.v.Elem(), true
+	case reflect.Bool:
+		b, ok := value.(bool)
+		return reflect.ValueOf(b).Convert(t), ok
+	case reflect.String:
+		s, ok := value.(string)
+		return reflect.ValueOf(s).Convert(t), ok
+	case reflect.Float32, reflect.Float64:
+		n, ok := value.(float64)
+		return reflect.ValueOf(n).Convert(t), ok
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 ||
+			reflect.Zero(t).OverflowInt(int64(n)) {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(int64(n)).Convert(t), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) || n < 0 || n >= math.MaxUint64 ||
+			reflect.Zero(t).OverflowUint(uint64(n)) {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(uint64(n)).Convert(t), true
+	default:
+		return reflect.Value{}, false
+	}
+}
+
+// fromGo converts a Go value to the Lox value it represents. Every numeric
+// type becomes a Lox number, structs and pointers to them are bound as
+// instances, and Lox values handed back by the host pass through unchanged.
+func fromGo(v reflect.Value) (Value, bool) {
+	if !v.IsValid() {
+		return nil, true
+	}
+	if (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && v.IsNil() {
+		return nil, true
+	}
+	// Lox values are pointers to structs too, so they must be let through
+	// before anything is bound.
+	if v.CanInterface() {
+		switch value := v.Interface().(type) {
+		case interp.LoxCallable, interp.Object:
+			return value, true
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.String:
+		return v.String(), true
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Convert(float64Type).Float(), true
+	case reflect.Interface:
+		return fromGo(v.Elem())
+	case reflect.Pointer:
+		if v.Elem().Kind() == reflect.Struct {
+			return bind(v), true
+		}
+	case reflect.Struct:
+		if !v.CanAddr() {
+			// Copy so the bound fields can be assigned to.
+			ptr := reflect.New(v.Type())
+			ptr.Elem().Set(v)
+			return bind(ptr), true
+		}
+		return bind(v.Addr()), true
+	}
+	return nil, false
+}
+
+// loxTypeFor describes the Lox values accepted for a Go parameter type.
+func loxTypeFor(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Struct:
+		return "a " + t.Name() + " instance"
+	case reflect.Pointer:
+		return loxTypeFor(t.Elem())
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			return "a value implementing " + t.String()
+		}
+		return "a value"
+	default:
+		return "a value"
+	}
+}
@@ -0,0 +1,73 @@
+package lox
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToGo(t *testing.T) {
+	type label string
+
+	tests := []struct {
+		name     string
+		value    Value
+		target   any
+		expected any
+		ok       bool
+	}{
+		{"number to float64", 1.5, float64(0), 1.5, true},
+		{"number to float32", 1.5, float32(0), float32(1.5), true},
+		{"whole number to int", 3.0, int(0), 3, true},
+		{"fraction to int", 3.5, int(0), nil, false},
+		{"overflowing int8", 200.0, int8(0), nil, false},
+		{"huge int64", math.MaxFloat64, int64(0), nil, false},
+		{"negative uint", -1.0, uint(0), nil, false},
+		{"whole number to uint16", 65535.0, uint16(0), uint16(65535), true},
+		{"string", "s", "", "s", true},
+		{"named string", "s", label(""), label("s"), true},
+		{"number to string", 1.0, "", nil, false},
+		{"boolean", true, false, true, true},
+		{"nil to boolean", nil, false, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := toGo(tt.value, reflect.TypeOf(tt.target))
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, v.Interface())
+			}
+		})
+	}
+}
+
+func TestFromGo(t *testing.T) {
+	var nilPointer *int
+
+	tests := []struct {
+		name     string
+		value    any
+		expected Value
+		ok       bool
+	}{
+		{"int", 7, 7.0, true},
+		{"uint64", uint64(7), 7.0, true},
+		{"float32", float32(0.5), 0.5, true},
+		{"string", "s", "s", true},
+		{"boolean", true, true, true},
+		{"nil", nil, nil, true},
+		{"nil pointer", nilPointer, nil, true},
+		{"map", map[string]int{}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := fromGo(reflect.ValueOf(tt.value))
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
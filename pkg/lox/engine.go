@@ -0,0 +1,208 @@
+// Package lox embeds the Lox scripting language in Go programs.
+//
+// An Engine holds one interpreter session: globals defined by one call to
+// Eval or RunFile are visible to every later call.
+//
+//	engine := lox.NewEngine()
+//	engine.SetStdout(&buf)
+//	value, err := engine.Eval(ctx, "var greeting = \"hi\"; greeting;")
+package lox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	interp "github.com/mikowitz/go-lox/internal/lox"
+)
+
+// Value is a Lox value as seen from Go: nil, a bool, a float64, a string,
+// or an opaque function, class, instance or list, such as the one split
+// returns.
+type Value = any
+
+// RuntimeError is returned when a script fails while running. Use
+// errors.As to inspect its Code, location and stack trace.
+type RuntimeError = interp.RuntimeError
+
+// CompileError is returned when a script fails to scan, parse or resolve.
+// It holds every problem found, not just the first.
+type CompileError = interp.CompileError
+
+// Diagnostic describes a single problem found in a script.
+type Diagnostic = interp.Diagnostic
+
+// CallFrame is one entry in a RuntimeError's stack trace.
+type CallFrame = interp.CallFrame
+
+// Span locates a Diagnostic or CallFrame in its source.
+type Span = interp.Span
+
+// Stable codes carried by RuntimeError.Code and Diagnostic.Code.
+const (
+	CodeUnexpectedCharacter = interp.CodeUnexpectedCharacter
+	CodeUnterminatedString  = interp.CodeUnterminatedString
+	CodeInvalidNumber       = interp.CodeInvalidNumber
+	CodeSyntax              = interp.CodeSyntax
+	CodeResolution          = interp.CodeResolution
+	CodeRuntime             = interp.CodeRuntime
+	CodeOperandNotNumber    = interp.CodeOperandNotNumber
+	CodeOperandsNotNumbers  = interp.CodeOperandsNotNumbers
+	CodeInvalidOperands     = interp.CodeInvalidOperands
+	CodeUndefinedVariable   = interp.CodeUndefinedVariable
+	CodeUndefinedProperty   = interp.CodeUndefinedProperty
+	CodeNotCallable         = interp.CodeNotCallable
+	CodeArityMismatch       = interp.CodeArityMismatch
+	CodeNotInstance         = interp.CodeNotInstance
+	CodeSuperclassNotClass  = interp.CodeSuperclassNotClass
+	CodeInvalidArgument     = interp.CodeInvalidArgument
+	CodeTypeMismatch        = interp.CodeTypeMismatch
+	CodeCanceled            = interp.CodeCanceled
+	CodeStackOverflow       = interp.CodeStackOverflow
+	CodeStepLimit           = interp.CodeStepLimit
+	CodeAllocationLimit     = interp.CodeAllocationLimit
+	CodeIO                  = interp.CodeIO
+)
+
+// Capability names a kind of access to the host that builtins need.
+type Capability = interp.Capability
+
+// The capabilities a host can grant with SetCapabilities.
+const (
+	CapabilityFSRead  = interp.CapabilityFSRead
+	CapabilityFSWrite = interp.CapabilityFSWrite
+	CapabilityTime    = interp.CapabilityTime
+	CapabilityEnv     = interp.CapabilityEnv
+	CapabilityStdout  = interp.CapabilityStdout
+)
+
+// Limits bounds the resources a script may use. See SetLimits.
+type Limits = interp.Limits
+
+// DefaultMaxCallDepth is the call depth allowed when Limits.MaxCallDepth is
+// zero.
+const DefaultMaxCallDepth = interp.DefaultMaxCallDepth
+
+// Engine evaluates Lox source against a persistent set of globals. An
+// Engine is not safe for concurrent use, and natives it runs can't call
+// back into Eval or RunFile.
+type Engine struct {
+	lox    *interp.Lox
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewEngine creates an Engine whose globals are just the prelude natives,
+// such as len, str and split, writing script output to os.Stdout and
+// diagnostics to os.Stderr. Builtins that touch the host, clock included,
+// wait for SetCapabilities.
+func NewEngine() *Engine {
+	e := &Engine{
+		lox:    &interp.Lox{},
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+	e.lox.SetOutput(e.stdout, e.stderr)
+	return e
+}
+
+// SetStdout directs the output of print statements to w.
+func (e *Engine) SetStdout(w io.Writer) {
+	e.stdout = w
+	e.lox.SetOutput(e.stdout, e.stderr)
+}
+
+// SetStderr directs rendered diagnostics to w. The same errors are also
+// returned from Eval and RunFile, so io.Discard is a reasonable choice for
+// hosts that handle them there.
+func (e *Engine) SetStderr(w io.Writer) {
+	e.stderr = w
+	e.lox.SetOutput(e.stdout, e.stderr)
+}
+
+// SetCapabilities grants exactly caps, replacing any earlier grant. An
+// Engine starts with none, so scripts can't touch the host until it opts
+// in: fs.read installs readFile, fs.write writeFile, time clock, env getenv
+// and stdout write. The print statement is part of the language and always
+// writes to the Engine's stdout.
+func (e *Engine) SetCapabilities(caps ...Capability) {
+	e.lox.SetCapabilities(interp.NewCapabilities(caps...))
+}
+
+// SetLimits bounds the call depth, loop iterations and calls, allocations
+// and string memory of every later Eval or RunFile, so untrusted scripts
+// can be run safely. Exceeding a limit raises a RuntimeError with
+// CodeStackOverflow, CodeStepLimit or CodeAllocationLimit.
+func (e *Engine) SetLimits(limits Limits) {
+	e.lox.SetLimits(limits)
+}
+
+// Eval runs source and returns the value of its final statement if that is
+// a bare expression, or nil otherwise. The error is a *CompileError if the
+// source never started running and a *RuntimeError if it failed part way.
+//
+// Evaluation doesn't start if ctx is already done, and a running script
+// stops at its next loop iteration or call once it is, with a RuntimeError
+// whose Code is CodeCanceled. Either way errors.Is reports the error as
+// context.Canceled or context.DeadlineExceeded.
+func (e *Engine) Eval(ctx context.Context, source string) (Value, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return e.lox.Eval(ctx, source)
+}
+
+// RunFile runs the script at path, honoring ctx as Eval does. Diagnostics
+// are reported against the file's name.
+func (e *Engine) RunFile(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	_, err := e.lox.EvalFile(ctx, path)
+	return err
+}
+
+// Define exposes fn to scripts as a global function called name.
+//
+// fn is either a func([]Value) (Value, error), which receives the
+// arguments as they are, or a function whose parameters are bools, strings,
+// numeric types or Value and which returns nothing, a value, an error, or a
+// value and an error. Arguments are converted to the parameter types, with
+// a mismatch raising a RuntimeError with CodeInvalidArgument; numbers only
+// convert to integer types when they are whole. Results convert back, with
+// every numeric type becoming a Lox number. An error returned by fn is
+// raised as a RuntimeError at the call.
+//
+// arity is the number of arguments scripts must pass, and must match the
+// parameter count of a typed function.
+func (e *Engine) Define(name string, fn any, arity int) error {
+	call, err := wrapFunc(name, fn, arity)
+	if err != nil {
+		return err
+	}
+	e.lox.Define(name, interp.NewNativeFunction(name, arity, call))
+	return nil
+}
+
+// Bind exposes value, a struct or a pointer to one, to scripts as a global
+// instance called name. Its exported fields become properties and its
+// exported methods become methods, converted as described for Define.
+// Nested structs are bound the same way. Scripts see and change the
+// original only when value is a pointer; a struct is copied.
+//
+// Assigning a value of the wrong type to a field raises a RuntimeError with
+// CodeTypeMismatch.
+func (e *Engine) Bind(name string, value any) error {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%s: expected a struct or a pointer to one, got %T", name, value)
+	}
+	object, _ := fromGo(reflect.ValueOf(value))
+	e.lox.Define(name, object)
+	return nil
+}
@@ -0,0 +1,391 @@
+package lox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEngine(stdout, stderr io.Writer) *Engine {
+	engine := NewEngine()
+	engine.SetStdout(stdout)
+	engine.SetStderr(stderr)
+	return engine
+}
+
+func TestEngine_Eval(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected Value
+	}{
+		{"number", "1 + 2;", 3.0},
+		{"string", "\"a\" + \"b\";", "ab"},
+		{"boolean", "1 < 2;", true},
+		{"nil", "nil;", nil},
+		{"statement", "var a = 1;", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(io.Discard, io.Discard)
+
+			value, err := engine.Eval(context.Background(), tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestEngine_Eval_PersistsGlobals(t *testing.T) {
+	var stdout bytes.Buffer
+	engine := newTestEngine(&stdout, io.Discard)
+	ctx := context.Background()
+
+	_, err := engine.Eval(ctx, "class Counter { init() { this.n = 0; } bump() { this.n = this.n + 1; return this.n; } }")
+	require.NoError(t, err)
+	_, err = engine.Eval(ctx, "var counter = Counter(); counter.bump();")
+	require.NoError(t, err)
+	value, err := engine.Eval(ctx, "print counter.bump(); counter.n;")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2.0, value)
+	assert.Equal(t, "2\n", stdout.String())
+}
+
+func TestEngine_Eval_Errors(t *testing.T) {
+	var stderr bytes.Buffer
+	engine := newTestEngine(io.Discard, &stderr)
+	ctx := context.Background()
+
+	_, err := engine.Eval(ctx, "print ;")
+	var compileErr *CompileError
+	require.ErrorAs(t, err, &compileErr)
+	assert.Equal(t, CodeSyntax, compileErr.Diagnostics[0].Code)
+
+	_, err = engine.Eval(ctx, "fun f() { return missing; }\nf();")
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeUndefinedVariable, runtimeErr.Code)
+	require.Len(t, runtimeErr.Trace, 2)
+	assert.Equal(t, "f", runtimeErr.Trace[0].Function)
+	assert.Equal(t, 2, runtimeErr.Trace[1].Span.Line)
+
+	assert.Contains(t, stderr.String(), "undefined variable 'missing'")
+}
+
+func TestEngine_Eval_CanceledContext(t *testing.T) {
+	var stdout bytes.Buffer
+	engine := newTestEngine(&stdout, io.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := engine.Eval(ctx, "print 1;")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Empty(t, stdout.String())
+}
+
+func TestEngine_RunFile(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	engine := newTestEngine(&stdout, &stderr)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib.lox")
+	require.NoError(t, os.WriteFile(lib, []byte("fun double(n) { return n * 2; }"), 0644))
+	broken := filepath.Join(dir, "broken.lox")
+	require.NoError(t, os.WriteFile(broken, []byte("double(\"x\");"), 0644))
+
+	require.NoError(t, engine.RunFile(ctx, lib))
+	value, err := engine.Eval(ctx, "double(21);")
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, value)
+
+	err = engine.RunFile(ctx, broken)
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Contains(t, stderr.String(), broken+":1:")
+
+	assert.Error(t, engine.RunFile(ctx, filepath.Join(dir, "missing.lox")))
+}
+
+func TestEngine_Define(t *testing.T) {
+	tests := []struct {
+		name     string
+		native   string
+		fn       any
+		arity    int
+		source   string
+		expected Value
+	}{
+		{
+			name:     "raw arguments",
+			native:   "size",
+			fn:       func(args []Value) (Value, error) { return len(args[0].(string)), nil },
+			arity:    1,
+			source:   "size(\"four\");",
+			expected: 4.0,
+		},
+		{
+			name:     "raw nil result",
+			native:   "none",
+			fn:       func(args []Value) (Value, error) { return nil, nil },
+			arity:    0,
+			source:   "none();",
+			expected: nil,
+		},
+		{
+			name:     "raw nil argument passed through",
+			native:   "identity",
+			fn:       func(args []Value) (Value, error) { return args[0], nil },
+			arity:    1,
+			source:   "identity(nil);",
+			expected: nil,
+		},
+		{
+			name:     "typed parameters",
+			native:   "repeat",
+			fn:       func(s string, n int) string { return strings.Repeat(s, n) },
+			arity:    2,
+			source:   "repeat(\"ab\", 3);",
+			expected: "ababab",
+		},
+		{
+			name:     "boolean result",
+			native:   "positive",
+			fn:       func(n float64) bool { return n > 0 },
+			arity:    1,
+			source:   "positive(-1);",
+			expected: false,
+		},
+		{
+			name:     "value parameter accepts nil",
+			native:   "isNil",
+			fn:       func(v Value) bool { return v == nil },
+			arity:    1,
+			source:   "isNil(nil);",
+			expected: true,
+		},
+		{
+			name:     "no result",
+			native:   "noop",
+			fn:       func() {},
+			arity:    0,
+			source:   "noop();",
+			expected: nil,
+		},
+		{
+			name:     "value and nil error",
+			native:   "byte",
+			fn:       func(n int64) (uint8, error) { return uint8(n), nil },
+			arity:    1,
+			source:   "byte(255);",
+			expected: 255.0,
+		},
+		{
+			name:     "lox values pass through",
+			native:   "identity",
+			fn:       func(f Value) Value { return f },
+			arity:    1,
+			source:   "fun one() { return 1; } identity(one)();",
+			expected: 1.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(io.Discard, io.Discard)
+			require.NoError(t, engine.Define(tt.native, tt.fn, tt.arity))
+
+			value, err := engine.Eval(context.Background(), tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestEngine_Define_RuntimeErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		source          string
+		expectedCode    string
+		expectedMessage string
+	}{
+		{
+			name:            "string for an integer",
+			source:          "repeat(\"a\", \"b\");",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 2 to repeat must be an integer, got string",
+		},
+		{
+			name:            "fractional integer",
+			source:          "repeat(\"a\", 1.5);",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 2 to repeat must be an integer, got number",
+		},
+		{
+			name:            "nil for a string",
+			source:          "repeat(nil, 1);",
+			expectedCode:    CodeInvalidArgument,
+			expectedMessage: "argument 1 to repeat must be a string, got nil",
+		},
+		{
+			name:            "error from the host",
+			source:          "repeat(\"a\", -1);",
+			expectedCode:    CodeRuntime,
+			expectedMessage: "count must not be negative",
+		},
+		{
+			name:            "wrong arity",
+			source:          "repeat(\"a\");",
+			expectedCode:    CodeArityMismatch,
+			expectedMessage: "expected 2 arguments but got 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(io.Discard, io.Discard)
+			require.NoError(t, engine.Define("repeat", func(s string, n int) (string, error) {
+				if n < 0 {
+					return "", errors.New("count must not be negative")
+				}
+				return strings.Repeat(s, n), nil
+			}, 2))
+
+			_, err := engine.Eval(context.Background(), tt.source)
+
+			var runtimeErr *RuntimeError
+			require.ErrorAs(t, err, &runtimeErr)
+			assert.Equal(t, tt.expectedCode, runtimeErr.Code)
+			assert.Equal(t, tt.expectedMessage, runtimeErr.Error())
+			assert.Equal(t, 1, runtimeErr.Token.Line)
+		})
+	}
+}
+
+func TestEngine_Define_InvalidFunctions(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    any
+		arity int
+	}{
+		{"not a function", 42, 0},
+		{"nil function", (func())(nil), 0},
+		{"arity mismatch", func(a, b string) {}, 1},
+		{"negative raw arity", func([]Value) (Value, error) { return nil, nil }, -1},
+		{"variadic", func(args ...string) {}, 0},
+		{"unsupported parameter", func(m map[string]int) {}, 1},
+		{"second result not an error", func() (int, int) { return 0, 0 }, 0},
+		{"too many results", func() (int, int, error) { return 0, 0, nil }, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(io.Discard, io.Discard)
+			assert.Error(t, engine.Define("bad", tt.fn, tt.arity))
+		})
+	}
+}
+
+func TestEngine_Eval_Timeout(t *testing.T) {
+	engine := newTestEngine(io.Discard, io.Discard)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := engine.Eval(ctx, "while (true) {}")
+
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeCanceled, runtimeErr.Code)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The engine stays usable once the deadline has passed.
+	value, err := engine.Eval(context.Background(), "1 + 1;")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, value)
+}
+
+func TestEngine_SetLimits(t *testing.T) {
+	engine := newTestEngine(io.Discard, io.Discard)
+	engine.SetLimits(Limits{MaxSteps: 100})
+	ctx := context.Background()
+
+	_, err := engine.Eval(ctx, "fun loop() { while (true) {} }\nloop();")
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeStepLimit, runtimeErr.Code)
+
+	_, err = engine.Eval(ctx, "fun deep() { return deep(); }\ndeep();")
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeStepLimit, runtimeErr.Code)
+
+	engine.SetLimits(Limits{})
+	_, err = engine.Eval(ctx, "deep();")
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeStackOverflow, runtimeErr.Code)
+	assert.Len(t, runtimeErr.Trace, DefaultMaxCallDepth+1)
+}
+
+func TestEngine_SetCapabilities(t *testing.T) {
+	var stdout bytes.Buffer
+	engine := newTestEngine(&stdout, io.Discard)
+	ctx := context.Background()
+
+	_, err := engine.Eval(ctx, `write("hidden");`)
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, CodeUndefinedVariable, runtimeErr.Code)
+
+	engine.SetCapabilities(CapabilityStdout, CapabilityTime)
+	_, err = engine.Eval(ctx, `write("shown"); clock();`)
+	require.NoError(t, err)
+	assert.Equal(t, "shown", stdout.String())
+
+	engine.SetCapabilities(CapabilityTime)
+	_, err = engine.Eval(ctx, `write("hidden");`)
+	assert.ErrorAs(t, err, &runtimeErr)
+}
+
+func TestEngine_Prelude(t *testing.T) {
+	engine := newTestEngine(io.Discard, io.Discard)
+
+	value, err := engine.Eval(context.Background(), `upper(split("hello lox", " ").get(1)) + str(len("abc"));`)
+	require.NoError(t, err)
+	assert.Equal(t, "LOX3", value)
+}
+
+func TestEngine_Eval_Reentrant(t *testing.T) {
+	engine := newTestEngine(io.Discard, io.Discard)
+	require.NoError(t, engine.Define("nested", func() (Value, error) {
+		return engine.Eval(context.Background(), "1;")
+	}, 0))
+
+	_, err := engine.Eval(context.Background(), "nested();")
+	var runtimeErr *RuntimeError
+	require.ErrorAs(t, err, &runtimeErr)
+	assert.Equal(t, "can't evaluate source while a script is running", runtimeErr.Error())
+
+	value, err := engine.Eval(context.Background(), "1;")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+}
+
+func TestEngine_Eval_DeeplyNestedInput(t *testing.T) {
+	engine := newTestEngine(io.Discard, io.Discard)
+
+	_, err := engine.Eval(context.Background(), strings.Repeat("-", 3_000_000)+"1;")
+	var compileErr *CompileError
+	require.ErrorAs(t, err, &compileErr)
+	require.Len(t, compileErr.Diagnostics, 1)
+	assert.Equal(t, "too deeply nested", compileErr.Diagnostics[0].Message)
+}